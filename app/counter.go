@@ -16,8 +16,9 @@ func counter() {
 		Border("1px solid #ccc").
 		Center()
 
-	count.Subscribe(func(v int) {
-		label.SetText(fmt.Sprintf("Count: %d", v))
+	core.RenderEffect(func() func() {
+		label.SetText(fmt.Sprintf("Count: %d", count.Get()))
+		return nil
 	})
 
 	// 2nd formulation
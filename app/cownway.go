@@ -63,11 +63,13 @@ func ConwayReactive() {
 				next[i][j] = liveRule(currAlive, liveNeighbors)
 			}
 		}
-		for i := range grid {
-			for j := range grid[i] {
-				grid[i][j].Alive.Set(next[i][j])
+		core.Batch(func() {
+			for i := range grid {
+				for j := range grid[i] {
+					grid[i][j].Alive.Set(next[i][j])
+				}
 			}
-		}
+		})
 	}
 
 	run := ui.NewButton("▶️ Step").Padding(8)
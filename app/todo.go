@@ -16,7 +16,7 @@ func todo() {
 	win := ui.NewWindow()
 
 	taskText := core.NewObservable("")
-	tasks := core.NewObservable([]*Task{})
+	tasks := core.NewObservableSlice[*Task]()
 
 	// Input
 	input := ui.NewTextField()
@@ -26,8 +26,20 @@ func todo() {
 	// Add Button
 	addBtn := ui.NewButton("Add").Padding(8)
 
-	// Task list container
-	list := ui.NewVBox().Padding(8)
+	// Task list, one row per task
+	list := ui.NewList(tasks, func(task *Task) ui.Widget {
+		checkbox := ui.NewCheckBox()
+		checkbox.SetChecked(task.Checked.Get())
+		checkbox.OnChange(func(checked bool) {
+			task.Checked.Set(checked)
+		})
+
+		label := ui.NewLabel(task.Label)
+
+		row := ui.NewHBox()
+		row.Add(checkbox, label)
+		return row
+	}).Padding(8)
 
 	// Header
 	header := ui.NewHBox()
@@ -45,22 +57,7 @@ func todo() {
 			Checked: core.NewObservable(false),
 		}
 		taskText.Set("")
-
-		current := tasks.Get()
-		tasks.Set(append(current, task))
-
-		checkbox := ui.NewCheckBox()
-		checkbox.SetChecked(false)
-		checkbox.OnChange(func(checked bool) {
-			task.Checked.Set(checked)
-			tasks.Set(tasks.Get())
-		})
-
-		label := ui.NewLabel(task.Label)
-
-		row := ui.NewHBox()
-		row.Add(checkbox, label)
-		list.Add(row)
+		tasks.Append(task)
 	})
 
 	remaining := core.Derive(func() string {
@@ -78,7 +75,7 @@ func todo() {
 
 	// Derived count of total tasks
 	taskCount := core.Derive(func() string {
-		return fmt.Sprintf("📦 Total tasks: %d", len(tasks.Get()))
+		return fmt.Sprintf("📦 Total tasks: %d", tasks.Len())
 	})
 
 	countLabel := ui.NewLabel("")
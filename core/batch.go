@@ -0,0 +1,213 @@
+package core
+
+import (
+	"container/heap"
+	"sync"
+	"sync/atomic"
+)
+
+// === Propagation ===
+//
+// Changes are propagated synchronously in two phases. Setting an observable
+// marks every dependent Derived stale (push-dirty) and queues it; the queue is
+// then flushed in topological order, lowest height first, recomputing each
+// stale node once and notifying its subscribers. Reading a stale Derived
+// recomputes it on the spot (pull-value), so reads made from subscribers
+// during a flush never observe an intermediate state.
+//
+// One goroutine propagates at a time. The first to start a change owns
+// propagation until its flush completes; changes it makes meanwhile, from
+// a Batch, subscriber or Effect, join its flush. A change made from any
+// other goroutine, such as a Resource fetch or a timer, waits for that
+// flush to complete and then propagates on its own, so it too has reached
+// every dependent by the time it returns.
+
+// observer is a computation that depends on one or more sources.
+type observer interface {
+	markStale()
+}
+
+// pendingNode is a unit of work waiting in the propagation queue.
+type pendingNode interface {
+	height() int
+	nodeID() uint64
+	flush()
+}
+
+var nodeIDs atomic.Uint64
+
+func nextNodeID() uint64 { return nodeIDs.Add(1) }
+
+var (
+	// propMu is held by the goroutine that owns propagation, whose id is
+	// propOwner. batchDepth and flushing belong to that goroutine.
+	propMu     sync.Mutex
+	propOwner  atomic.Uint64
+	batchDepth int
+	flushing   bool
+	afterFlush []func()
+
+	batchMu sync.Mutex
+	queue   nodeQueue
+	queued  = map[pendingNode]bool{}
+
+	// flushed lists the nodes flushed so far in the current propagation;
+	// lastFlush and flushes hold each node's latest index in it and its
+	// flush count, to detect update loops. Only the first loop found in a
+	// propagation is reported.
+	flushed   []pendingNode
+	lastFlush = map[pendingNode]int{}
+	flushes   = map[pendingNode]int{}
+	looped    bool
+)
+
+// Batch runs fn and defers subscriber and Derived notifications until it
+// returns. Each observable set inside fn notifies once with its final value,
+// and each affected Derived recomputes once. Batches may be nested; only the
+// outermost one flushes.
+func Batch(fn func()) {
+	startBatch()
+	defer endBatch()
+	fn()
+}
+
+// startBatch makes the calling goroutine the owner of propagation, waiting
+// for another owner to finish first, and opens a batch. Both taking
+// ownership and finding it already taken cost a goid lookup, so a Set
+// inside a Batch pays one too; see the note in scope.go.
+func startBatch() {
+	if propMu.TryLock() {
+		propOwner.Store(goid())
+	} else if id := goid(); propOwner.Load() != id {
+		propMu.Lock()
+		propOwner.Store(id)
+	}
+	batchDepth++
+}
+
+// endBatch closes a batch. Closing the outermost one flushes the queue and
+// gives up ownership; inside a flush, it leaves the rest to that flush.
+func endBatch() {
+	batchDepth--
+	if batchDepth > 0 || flushing {
+		return
+	}
+	flushing = true
+	defer func() {
+		flushing = false
+		propOwner.Store(0)
+		propMu.Unlock()
+	}()
+
+	// Subscribers run outside of whatever computation triggered the flush.
+	// With no scope active anywhere there is none to leave, which spares
+	// looking up the goroutine.
+	if activeScopes.Load() == 0 {
+		drain()
+	} else {
+		withScope(nil, drain)
+	}
+}
+
+// onFlushed runs fn once the current flush has emptied the queue, before
+// propagation ends. It must be called inside a batch. Changes made by fn
+// are flushed in turn.
+func onFlushed(fn func()) {
+	afterFlush = append(afterFlush, fn)
+}
+
+// drain flushes queued nodes, then runs the onFlushed funcs, until neither
+// is left.
+func drain() {
+	for {
+		n, skip, loop := dequeue()
+		if n == nil {
+			if len(afterFlush) == 0 {
+				return
+			}
+			fns := afterFlush
+			afterFlush = nil
+			for _, fn := range fns {
+				fn()
+			}
+			continue
+		}
+		if skip {
+			if loop != nil {
+				reportError(nil, runawayError(n, loop))
+			}
+			if a, ok := n.(interface{ abandon() }); ok {
+				a.abandon()
+			}
+			continue
+		}
+		n.flush()
+	}
+}
+
+// enqueue adds n to the propagation queue unless it is already waiting.
+func enqueue(n pendingNode) {
+	batchMu.Lock()
+	if !queued[n] {
+		queued[n] = true
+		heap.Push(&queue, queueEntry{n, n.height(), n.nodeID()})
+	}
+	batchMu.Unlock()
+}
+
+// dequeue pops the lowest node in topological order, or returns nil and
+// ends the flush when the queue is empty. If n has already been flushed
+// maxFlushes times in this propagation it must be skipped; for the first
+// such node, loop holds the nodes flushed since its last flush.
+func dequeue() (n pendingNode, skip bool, loop []pendingNode) {
+	batchMu.Lock()
+	defer batchMu.Unlock()
+	if len(queue) == 0 {
+		flushed = nil
+		clear(lastFlush)
+		clear(flushes)
+		looped = false
+		return nil, false, nil
+	}
+	n = heap.Pop(&queue).(queueEntry).node
+	delete(queued, n)
+	if flushes[n]++; flushes[n] > maxFlushes {
+		if looped {
+			return n, true, nil
+		}
+		looped = true
+		return n, true, append([]pendingNode{}, flushed[lastFlush[n]+1:]...)
+	}
+	lastFlush[n] = len(flushed)
+	flushed = append(flushed, n)
+	return n, false, nil
+}
+
+type queueEntry struct {
+	node   pendingNode
+	height int
+	id     uint64
+}
+
+// nodeQueue orders pending nodes by height, then by creation order.
+type nodeQueue []queueEntry
+
+func (q nodeQueue) Len() int { return len(q) }
+
+func (q nodeQueue) Less(i, j int) bool {
+	if q[i].height != q[j].height {
+		return q[i].height < q[j].height
+	}
+	return q[i].id < q[j].id
+}
+
+func (q nodeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *nodeQueue) Push(x any) { *q = append(*q, x.(queueEntry)) }
+
+func (q *nodeQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
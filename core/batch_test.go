@@ -0,0 +1,100 @@
+package core
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetPropagatesBeforeReturning(t *testing.T) {
+	a := NewObservable(1)
+	b := NewObservable(2)
+	sum := Derive(func() int { return a.Get() + b.Get() })
+	doubled := Derive(func() int { return sum.Get() * 2 })
+
+	var seen int
+	stop := doubled.Subscribe(func(v int) { seen = v })
+	defer stop()
+
+	a.Set(10)
+	if seen != 24 {
+		t.Fatalf("subscriber saw %d after Set, want 24", seen)
+	}
+	if got := sum.Get(); got != 12 {
+		t.Fatalf("sum = %d, want 12", got)
+	}
+}
+
+func TestBatchNotifiesOnce(t *testing.T) {
+	a := NewObservable(1)
+	b := NewObservable(2)
+	sum := Derive(func() int { return a.Get() + b.Get() })
+
+	var calls []int
+	stop := sum.Subscribe(func(v int) { calls = append(calls, v) })
+	defer stop()
+
+	Batch(func() {
+		a.Set(10)
+		b.Set(20)
+		if len(calls) != 1 {
+			t.Errorf("notified inside Batch: %v", calls)
+		}
+	})
+	if len(calls) != 2 || calls[1] != 30 {
+		t.Fatalf("calls = %v, want [3 30]", calls)
+	}
+}
+
+func TestSetFromSubscriberJoinsFlush(t *testing.T) {
+	src := NewObservable(0)
+	mirror := NewObservable(0)
+	stop := src.Subscribe(func(v int) { mirror.Set(v) })
+	defer stop()
+
+	var seen int
+	stopMirror := mirror.Subscribe(func(v int) { seen = v })
+	defer stopMirror()
+
+	src.Set(5)
+	if seen != 5 {
+		t.Fatalf("mirror subscriber saw %d, want 5", seen)
+	}
+}
+
+func TestSetFromOtherGoroutineWaitsForFlush(t *testing.T) {
+	a := NewObservable(0)
+	b := NewObservable(0)
+	doubled := Derive(func() int { return b.Get() * 2 })
+
+	var seen atomic.Int64
+	stop := doubled.Subscribe(func(v int) { seen.Store(int64(v)) })
+	defer stop()
+
+	inBatch := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan int64)
+
+	go Batch(func() {
+		a.Set(1)
+		close(inBatch)
+		<-release
+	})
+	<-inBatch
+
+	go func() {
+		b.Set(21)
+		done <- seen.Load()
+	}()
+
+	select {
+	case v := <-done:
+		t.Fatalf("Set returned during another goroutine's batch, subscriber saw %d", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if v := <-done; v != 42 {
+		t.Fatalf("subscriber saw %d when Set returned, want 42", v)
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// === Cycle detection ===
+
+// maxFlushes bounds how often a single node may be flushed while one change
+// propagates. A subscriber or Effect that keeps setting an observable it
+// depends on would otherwise loop forever.
+const maxFlushes = 100
+
+// CycleError reports a Derived that depends on itself, or an update loop
+// that does not settle. Chain names the nodes involved in order, using the
+// debug names given with Named where there are any.
+type CycleError struct {
+	Chain []string
+	// Runaway is set for update loops, as opposed to a computation that
+	// reads its own value.
+	Runaway bool
+}
+
+func (e *CycleError) Error() string {
+	chain := strings.Join(e.Chain, " -> ")
+	if e.Runaway {
+		return fmt.Sprintf("core: update loop did not settle after %d flushes: %s", maxFlushes, chain)
+	}
+	return "core: dependency cycle: " + chain
+}
+
+// visiting holds the Derived nodes being brought up to date, outermost
+// first. Stale nodes are only refreshed by the goroutine that owns
+// propagation (see startBatch), so a single stack guarded by that
+// ownership suffices.
+var visiting []inspectable
+
+// enter records that n is being brought up to date and returns a func that
+// undoes it. If n already is, it returns a CycleError describing how n came
+// to be read again instead. It must be called by the propagation owner.
+func enter(n inspectable) (func(), error) {
+	for i, v := range visiting {
+		if v == n {
+			loop := append(visiting[i:len(visiting):len(visiting)], n)
+			return nil, &CycleError{Chain: labels(loop)}
+		}
+	}
+	visiting = append(visiting, n)
+	return func() { visiting = visiting[:len(visiting)-1] }, nil
+}
+
+// runawayError describes the loop that brought n back into the queue:
+// the nodes flushed since n was last flushed, then n itself.
+func runawayError(n pendingNode, since []pendingNode) *CycleError {
+	seen := map[pendingNode]bool{n: true}
+	loop := []any{n}
+	for _, m := range since {
+		if !seen[m] {
+			seen[m] = true
+			loop = append(loop, m)
+		}
+	}
+	loop = append(loop, n)
+	return &CycleError{Chain: labels(loop), Runaway: true}
+}
+
+func labels[N any](nodes []N) []string {
+	out := make([]string, len(nodes))
+	for i, n := range nodes {
+		out[i] = nodeLabel(n)
+	}
+	return out
+}
+
+// nodeLabel returns the debug name of n, or its kind and id.
+func nodeLabel(n any) string {
+	i, ok := n.(inspectable)
+	if !ok {
+		return fmt.Sprintf("%T", n)
+	}
+	if name := nodeName(i.nodeID()); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s#%d", i.inspect().kind, i.nodeID())
+}
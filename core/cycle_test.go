@@ -0,0 +1,34 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDerivedReadingItselfReportsCycle(t *testing.T) {
+	on := NewObservable(false)
+	var errs []error
+	var d *Derived[int]
+	dispose := Catch(func(err error) { errs = append(errs, err) }, func() {
+		d = Derive(func() int {
+			if on.Get() {
+				return d.Get() + 1
+			}
+			return 1
+		}).Named("self")
+	})
+	defer dispose()
+
+	on.Set(true)
+
+	var cycle *CycleError
+	if len(errs) == 0 || !errors.As(errs[0], &cycle) {
+		t.Fatalf("errors = %v, want a CycleError", errs)
+	}
+	if got := cycle.Chain; len(got) != 2 || got[0] != "self" || got[1] != "self" {
+		t.Fatalf("chain = %v, want [self self]", got)
+	}
+	if got := d.Get(); got != 2 {
+		t.Fatalf("value = %d, want 2", got)
+	}
+}
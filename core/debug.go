@@ -0,0 +1,217 @@
+package core
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+	"weak"
+)
+
+// === Graph introspection ===
+
+// GraphFormat selects the output of DumpGraph.
+type GraphFormat int
+
+const (
+	// GraphDOT writes a Graphviz digraph.
+	GraphDOT GraphFormat = iota
+	// GraphJSON writes {"nodes": [...], "edges": [...]}.
+	GraphJSON
+)
+
+// lastUpdate records when a node last changed.
+type lastUpdate struct{ nanos atomic.Int64 }
+
+func (u *lastUpdate) touch() { u.nanos.Store(time.Now().UnixNano()) }
+
+func (u *lastUpdate) time() time.Time {
+	if n := u.nanos.Load(); n != 0 {
+		return time.Unix(0, n)
+	}
+	return time.Time{}
+}
+
+// nodeInfo is a snapshot of one node for DumpGraph.
+type nodeInfo struct {
+	kind        string
+	subscribers int
+	updated     time.Time
+	sources     []internalObservable
+	observers   []observer
+}
+
+// inspectable is implemented by every node in the graph.
+type inspectable interface {
+	nodeID() uint64
+	inspect() nodeInfo
+}
+
+// registry holds the nodes given a debug name. Entries are weak, so naming a
+// node does not keep it alive.
+var registry = struct {
+	sync.Mutex
+	nodes map[uint64]namedNode
+}{nodes: map[uint64]namedNode{}}
+
+type namedNode struct {
+	name string
+	node func() inspectable
+}
+
+func register[N any, P interface {
+	*N
+	inspectable
+}](p P, name string) {
+	wp := weak.Make((*N)(p))
+	registry.Lock()
+	defer registry.Unlock()
+	registry.nodes[p.nodeID()] = namedNode{name: name, node: func() inspectable {
+		if n := wp.Value(); n != nil {
+			return P(n)
+		}
+		return nil
+	}}
+}
+
+func unregister(id uint64) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.nodes, id)
+}
+
+// nodeName returns the debug name of the node with id, or "" if it has none.
+func nodeName(id uint64) string {
+	registry.Lock()
+	defer registry.Unlock()
+	return registry.nodes[id].name
+}
+
+type graphNode struct {
+	ID          uint64     `json:"id"`
+	Name        string     `json:"name,omitempty"`
+	Kind        string     `json:"kind"`
+	Subscribers int        `json:"subscribers"`
+	Dependents  int        `json:"dependents"`
+	Updated     *time.Time `json:"updated,omitempty"`
+}
+
+type graphEdge struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// DumpGraph writes the live dependency graph around every named node:
+// the named nodes, everything they depend on and everything that depends on
+// them. Each node reports its Subscribe callback count, its number of
+// dependent computations and the time it last changed.
+func DumpGraph(w io.Writer, format GraphFormat) error {
+	nodes, edges := snapshotGraph()
+	switch format {
+	case GraphDOT:
+		return writeDOT(w, nodes, edges)
+	case GraphJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Nodes []graphNode `json:"nodes"`
+			Edges []graphEdge `json:"edges"`
+		}{nodes, edges})
+	default:
+		return fmt.Errorf("core: unknown graph format %d", format)
+	}
+}
+
+func snapshotGraph() ([]graphNode, []graphEdge) {
+	registry.Lock()
+	var pending []inspectable
+	names := map[uint64]string{}
+	for id, entry := range registry.nodes {
+		n := entry.node()
+		if n == nil {
+			delete(registry.nodes, id)
+			continue
+		}
+		names[id] = entry.name
+		pending = append(pending, n)
+	}
+	registry.Unlock()
+
+	seen := map[uint64]bool{}
+	edgeSet := map[graphEdge]bool{}
+	var nodes []graphNode
+	for len(pending) > 0 {
+		n := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if seen[n.nodeID()] {
+			continue
+		}
+		seen[n.nodeID()] = true
+
+		info := n.inspect()
+		node := graphNode{
+			ID:          n.nodeID(),
+			Name:        names[n.nodeID()],
+			Kind:        info.kind,
+			Subscribers: info.subscribers,
+			Dependents:  len(info.observers),
+		}
+		if !info.updated.IsZero() {
+			node.Updated = &info.updated
+		}
+		nodes = append(nodes, node)
+
+		for _, src := range info.sources {
+			if s, ok := src.(inspectable); ok {
+				edgeSet[graphEdge{From: s.nodeID(), To: n.nodeID()}] = true
+				pending = append(pending, s)
+			}
+		}
+		for _, obs := range info.observers {
+			if o, ok := obs.(inspectable); ok {
+				edgeSet[graphEdge{From: n.nodeID(), To: o.nodeID()}] = true
+				pending = append(pending, o)
+			}
+		}
+	}
+
+	slices.SortFunc(nodes, func(a, b graphNode) int { return cmp.Compare(a.ID, b.ID) })
+	edges := make([]graphEdge, 0, len(edgeSet))
+	for e := range edgeSet {
+		edges = append(edges, e)
+	}
+	slices.SortFunc(edges, func(a, b graphEdge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+	return nodes, edges
+}
+
+func writeDOT(w io.Writer, nodes []graphNode, edges []graphEdge) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	printf("digraph reactive {\n")
+	for _, n := range nodes {
+		name := n.Name
+		if name == "" {
+			name = fmt.Sprintf("%s#%d", n.Kind, n.ID)
+		}
+		text := fmt.Sprintf("%s\n%s, %d subscribers, %d dependents", name, n.Kind, n.Subscribers, n.Dependents)
+		if n.Updated != nil {
+			text += "\nupdated " + n.Updated.Format("15:04:05.000")
+		}
+		printf("\tn%d [label=%q];\n", n.ID, text)
+	}
+	for _, e := range edges {
+		printf("\tn%d -> n%d;\n", e.From, e.To)
+	}
+	printf("}\n")
+	return err
+}
@@ -0,0 +1,164 @@
+package core
+
+import "sync"
+
+// === Dependency graph ===
+
+// observerSet holds the computations that depend on a source.
+type observerSet struct {
+	mu sync.Mutex
+	m  map[observer]struct{}
+}
+
+// add registers obs and returns a func that removes it again.
+func (s *observerSet) add(obs observer) func() {
+	s.mu.Lock()
+	if s.m == nil {
+		s.m = map[observer]struct{}{}
+	}
+	s.m[obs] = struct{}{}
+	s.mu.Unlock()
+	return func() {
+		s.mu.Lock()
+		delete(s.m, obs)
+		s.mu.Unlock()
+	}
+}
+
+// markStale marks every registered observer stale.
+func (s *observerSet) markStale() {
+	for _, obs := range s.snapshot() {
+		obs.markStale()
+	}
+}
+
+func (s *observerSet) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.m)
+}
+
+func (s *observerSet) snapshot() []observer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	observers := make([]observer, 0, len(s.m))
+	for obs := range s.m {
+		observers = append(observers, obs)
+	}
+	return observers
+}
+
+// dependency is a source read by a computation, with the version it had
+// when it was read.
+type dependency struct {
+	src         internalObservable
+	version     uint64
+	unsubscribe func()
+}
+
+// dependencies is the input set of a Derived or Effect, in first-read order.
+// list is only replaced by the owner's run; mu lets others read it meanwhile.
+type dependencies struct {
+	mu   sync.Mutex
+	list []dependency
+}
+
+// track runs compute in s under a fresh tracker and returns its result
+// along with the sources it read, in first-read order. Computations owned by
+// s from its previous run are disposed first. If compute panics, the panic
+// is returned as a *PanicError for node, with the sources read until then.
+func track[T any](s *scope, node any, compute func() T) (T, []dependency, error) {
+	var read []dependency
+	seen := map[internalObservable]bool{}
+	depMu := sync.Mutex{}
+
+	tracker := &dependencyTracker{
+		add: func(obs internalObservable) {
+			depMu.Lock()
+			if !seen[obs] {
+				seen[obs] = true
+				read = append(read, dependency{src: obs, version: obs.version()})
+			}
+			depMu.Unlock()
+		},
+	}
+
+	s.disposeOwned()
+	s.tracker = tracker
+
+	var result T
+	var err error
+	withScope(s, func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(node, r)
+			}
+		}()
+		result = compute()
+	})
+	return result, read, err
+}
+
+// reconcile replaces the dependency set with read: obs is attached to new
+// sources and detached from those it no longer reads. It returns the height
+// of obs in the graph.
+func (ds *dependencies) reconcile(obs observer, read []dependency) int {
+	prev := make(map[internalObservable]func(), len(ds.list))
+	for _, dep := range ds.list {
+		prev[dep.src] = dep.unsubscribe
+	}
+	level := 0
+	for i, dep := range read {
+		if unsubscribe, ok := prev[dep.src]; ok {
+			read[i].unsubscribe = unsubscribe
+			delete(prev, dep.src)
+		} else {
+			read[i].unsubscribe = dep.src.addObserver(obs)
+		}
+		if h := dep.src.height(); h >= level {
+			level = h + 1
+		}
+	}
+	for _, unsubscribe := range prev {
+		unsubscribe()
+	}
+	ds.mu.Lock()
+	ds.list = read
+	ds.mu.Unlock()
+	return level
+}
+
+// changed brings upstream Derived nodes up to date and reports whether any
+// source moved past the version seen on the last run.
+func (ds *dependencies) changed() bool {
+	for _, dep := range ds.list {
+		if r, ok := dep.src.(interface{ refresh() }); ok {
+			r.refresh()
+		}
+		if dep.src.version() != dep.version {
+			return true
+		}
+	}
+	return false
+}
+
+// release detaches from every source.
+func (ds *dependencies) release() {
+	for _, dep := range ds.list {
+		dep.unsubscribe()
+	}
+	ds.mu.Lock()
+	ds.list = nil
+	ds.mu.Unlock()
+}
+
+// sources returns the current sources.
+func (ds *dependencies) sources() []internalObservable {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	out := make([]internalObservable, len(ds.list))
+	for i, dep := range ds.list {
+		out[i] = dep.src
+	}
+	return out
+}
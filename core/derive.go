@@ -8,113 +8,363 @@ import (
 // === Derived (reactive/computed) ===
 
 type Derived[T any] struct {
-	mu     sync.Mutex
-	value  T
-	subs   []func(T)
-	cancel context.CancelFunc
+	mu      sync.Mutex
+	value   T
+	subs    []listener[T]
+	nextSub int
+	cancel  context.CancelFunc
+
+	scope   *scope
+	compute func() T
+	equal   func(a, b T) bool
+
+	id        uint64
+	level     int
+	ver       uint64
+	observers observerSet
+	updated   lastUpdate
+	// stale is set when a dependency may have changed and must be checked;
+	// changed is set when value was recomputed but subscribers not yet told.
+	stale    bool
+	changed  bool
+	disposed bool
+	// err is set while compute panics; value then holds the last good one.
+	err error
+
+	// runMu serialises recomputations so that dependency sets stay consistent.
+	runMu sync.Mutex
+	deps  dependencies
 }
 
 // Derive creates a reactive computed observable.
+// Dependencies are re-tracked on every recomputation: observables read in the
+// latest run are subscribed to, and those no longer read are released.
+// Recomputation is synchronous and happens in topological order, so a
+// Derived never observes a mix of old and new dependency values. If T is
+// comparable, a recomputation that yields an equal value notifies no one.
+// A Derived can itself be read from another Derive, Effect or Map. A Derived
+// created inside another computation is owned by it and disposed when that
+// computation re-runs or is disposed.
 func Derive[T any](compute func() T) *Derived[T] {
-	d := &Derived[T]{}
+	return DeriveWithEqual(compute, defaultEqual[T]())
+}
 
+// DeriveWithEqual is like Derive but uses equal to decide whether a
+// recomputed value differs from the previous one. A nil equal notifies
+// after every recomputation.
+func DeriveWithEqual[T any](compute func() T, equal func(a, b T) bool) *Derived[T] {
 	ctx, cancel := context.WithCancel(context.Background())
-	d.cancel = cancel
+	d := &Derived[T]{
+		cancel:  cancel,
+		scope:   newScope(ctx),
+		compute: compute,
+		equal:   equal,
+		id:      nextNodeID(),
+	}
+	adopt(d.Dispose)
+	d.runMu.Lock()
+	d.value, d.err = d.run()
+	d.unlockRun()
+	return d
+}
 
-	var deps []internalObservable
-	depMu := sync.Mutex{}
+// run evaluates compute and reconciles the dependency set with the
+// observables it actually read. A panic in compute is reported and
+// returned. It must be called with runMu held.
+func (d *Derived[T]) run() (T, error) {
+	result, read, err := track(d.scope, d, d.compute)
+	if err != nil {
+		reportError(d.scope, err)
+	}
 
-	tracker := &dependencyTracker{
-		add: func(obs internalObservable) {
-			depMu.Lock()
-			deps = append(deps, obs)
-			depMu.Unlock()
-		},
+	d.mu.Lock()
+	disposed := d.disposed
+	d.mu.Unlock()
+	if disposed {
+		return result, err
 	}
 
-	result := runWithTracker(ctx, tracker, compute)
-	d.value = result
+	level := d.deps.reconcile(d, read)
 
-	update := func() {
-		newVal := compute()
-		d.mu.Lock()
-		d.value = newVal
-		for _, sub := range d.subs {
-			go sub(newVal) // async dispatch
-		}
+	d.mu.Lock()
+	d.level = level
+	d.mu.Unlock()
+
+	return result, err
+}
+
+// unlockRun releases runMu, first detaching d from its sources if it was
+// disposed meanwhile.
+func (d *Derived[T]) unlockRun() {
+	d.mu.Lock()
+	disposed := d.disposed
+	d.mu.Unlock()
+	if disposed {
+		d.deps.release()
+	}
+	d.runMu.Unlock()
+}
+
+// markStale flags d and everything downstream of it for checking, and
+// queues d for the current flush.
+func (d *Derived[T]) markStale() {
+	d.mu.Lock()
+	if d.stale || d.disposed {
 		d.mu.Unlock()
+		return
 	}
+	d.stale = true
+	d.mu.Unlock()
+	d.observers.markStale()
+	enqueue(d)
+}
 
-	for _, dep := range deps {
-		dep.addSubscriber(func() {
-			go update()
-		})
+// refresh brings d up to date if it is stale. It recomputes only when a
+// dependency actually changed, and reports nothing to subscribers; that is
+// left to flush so each one is notified once, in order. Like tryRefresh, it
+// must be called by the goroutine that owns propagation; readers use pull.
+func (d *Derived[T]) refresh() {
+	if err := d.tryRefresh(); err != nil {
+		reportError(d.scope, err)
 	}
+}
 
-	return d
+// tryRefresh is refresh, but returns a CycleError instead of deadlocking
+// when d is read while it is already being brought up to date on the same
+// goroutine; d then keeps its previous value.
+func (d *Derived[T]) tryRefresh() error {
+	d.mu.Lock()
+	stale := d.stale && !d.disposed
+	d.mu.Unlock()
+	if !stale {
+		return nil
+	}
+
+	leave, err := enter(d)
+	if err != nil {
+		return err
+	}
+	defer leave()
+
+	d.runMu.Lock()
+	defer d.unlockRun()
+
+	d.mu.Lock()
+	stale = d.stale && !d.disposed
+	d.mu.Unlock()
+	if !stale {
+		return nil
+	}
+
+	if !d.deps.changed() {
+		d.mu.Lock()
+		d.stale = false
+		d.mu.Unlock()
+		return nil
+	}
+
+	newVal, err := d.run()
+
+	// value only changes under runMu, so it can be compared unlocked; equal
+	// is user code and must not run with d.mu held.
+	d.mu.Lock()
+	old := d.value
+	d.mu.Unlock()
+	same := err == nil && d.equal != nil && d.equal(old, newVal)
+
+	d.mu.Lock()
+	d.stale = false
+	if (err == nil) != (d.err == nil) {
+		d.ver++
+	}
+	d.err = err
+	if err == nil && !same {
+		d.value = newVal
+		d.ver++
+		d.changed = true
+		d.updated.touch()
+	}
+	d.mu.Unlock()
+	return nil
 }
 
-func (d *Derived[T]) Get() T {
+// pull is tryRefresh for readers. A stale d is only brought up to date by
+// the goroutine that owns propagation, so a reader on another goroutine
+// waits for the running flush, which usually refreshes d itself.
+func (d *Derived[T]) pull() error {
 	d.mu.Lock()
-	val := d.value
+	stale := d.stale && !d.disposed
 	d.mu.Unlock()
-	return val
+	if !stale {
+		return nil
+	}
+	startBatch()
+	defer endBatch()
+	return d.tryRefresh()
 }
 
-func (d *Derived[T]) Subscribe(sub func(T)) {
+// abandon drops a pending flush of d, so the next change propagates again.
+func (d *Derived[T]) abandon() {
 	d.mu.Lock()
-	d.subs = append(d.subs, sub)
-	val := d.value
+	d.stale = false
+	d.changed = false
 	d.mu.Unlock()
-	sub(val)
 }
 
-func Map[T any, U any](d *Derived[T], f func(T) U) *Derived[U] {
-	return Derive(func() U {
-		return f(d.Get())
-	})
+func (d *Derived[T]) addObserver(obs observer) func() { return d.observers.add(obs) }
+
+func (d *Derived[T]) height() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.level
 }
 
-// === Dependency Tracking Context ===
+func (d *Derived[T]) version() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ver
+}
 
-type contextKey string
+func (d *Derived[T]) nodeID() uint64 { return d.id }
 
-var trackerKey = contextKey("reactive")
+func (d *Derived[T]) flush() {
+	d.refresh()
 
-type dependencyTracker struct {
-	add func(internalObservable)
+	d.mu.Lock()
+	if !d.changed {
+		d.mu.Unlock()
+		return
+	}
+	d.changed = false
+	val := d.value
+	subs := d.subs
+	d.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.call(d, val)
+	}
 }
 
-func runWithTracker[T any](ctx context.Context, tracker *dependencyTracker, compute func() T) T {
-	prevCtxMu.Lock()
-	prev := currentCtx
-	currentCtx = context.WithValue(ctx, trackerKey, tracker)
-	prevCtxMu.Unlock()
+// Get returns the current value, recomputing it first if a dependency has
+// changed since the last run. Reading a Derived inside a computation makes
+// it a dependency, just like reading an Observable. Reading d from its own
+// computation, directly or through other Derived nodes, reports a
+// CycleError and returns the previous value without tracking it.
+func (d *Derived[T]) Get() T {
+	d.track()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.value
+}
 
-	val := compute()
+// Err returns the panic from the latest recomputation as a *PanicError, or
+// nil if it succeeded. While it is set, Get returns the last value computed
+// without error. Err is tracked like Get.
+func (d *Derived[T]) Err() error {
+	d.track()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
 
-	prevCtxMu.Lock()
-	currentCtx = prev
-	prevCtxMu.Unlock()
+// track refreshes d and records it as a dependency of the running
+// computation, unless that would close a cycle.
+func (d *Derived[T]) track() {
+	if err := d.pull(); err != nil {
+		reportError(d.scope, err)
+		return
+	}
+	trackObservable(d)
+}
 
-	return val
+// Subscribe calls sub with the current value and after every recomputation.
+// The returned func removes the subscription.
+func (d *Derived[T]) Subscribe(sub func(T)) func() {
+	if err := d.pull(); err != nil {
+		reportError(d.scope, err)
+	}
+	l := newListener(0, sub)
+	d.mu.Lock()
+	val := d.value
+	d.nextSub++
+	l.id = d.nextSub
+	d.subs = append(d.subs, l)
+	d.mu.Unlock()
+	l.call(d, val)
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.subs = removeByID(d.subs, l.id)
+	}
 }
 
-var (
-	currentCtx context.Context
-	prevCtxMu  sync.Mutex
-)
+// Dispose detaches d from all its upstream observables and drops its
+// subscribers. A disposed Derived keeps its last value and never recomputes.
+func (d *Derived[T]) Dispose() {
+	d.mu.Lock()
+	d.disposed = true
+	d.stale = false
+	d.changed = false
+	d.subs = nil
+	d.mu.Unlock()
+
+	// Dispose may be called while d runs, for instance by an error boundary
+	// handling its panic on the same goroutine; that run then detaches d
+	// when it finishes, in unlockRun.
+	if d.runMu.TryLock() {
+		d.unlockRun()
+	}
 
-// ==== Observable hook ====
+	d.scope.disposeOwned()
+	d.cancel()
+	unregister(d.id)
+}
 
-func trackObservable(obs internalObservable) {
-	prevCtxMu.Lock()
-	ctx := currentCtx
-	prevCtxMu.Unlock()
+// Named gives d a debug name and registers it for DumpGraph.
+func (d *Derived[T]) Named(name string) *Derived[T] {
+	register(d, name)
+	return d
+}
 
-	if ctx != nil {
-		if tracker, ok := ctx.Value(trackerKey).(*dependencyTracker); ok && tracker != nil {
-			tracker.add(obs)
-		}
+func (d *Derived[T]) inspect() nodeInfo {
+	d.mu.Lock()
+	subscribers := len(d.subs)
+	d.mu.Unlock()
+	return nodeInfo{
+		kind:        "derived",
+		subscribers: subscribers,
+		updated:     d.updated.time(),
+		sources:     d.deps.sources(),
+		observers:   d.observers.snapshot(),
 	}
 }
+
+// Map derives a value of the same type from d, allowing
+// Derive(...).Map(...) chains.
+func (d *Derived[T]) Map(f func(T) T) *Derived[T] {
+	return Map[T, T](d, f)
+}
+
+// WritableDerived is a Derived whose writes are forwarded to a setter.
+type WritableDerived[T any] struct {
+	*Derived[T]
+	set func(T)
+}
+
+// DeriveWritable creates a computed observable that can also be written.
+// get is tracked exactly like a Derive compute func; Set calls set, which
+// should update the sources get reads, inside a Batch so that the new value
+// is propagated once. For example, a Fahrenheit view of a Celsius value:
+//
+//	f := core.DeriveWritable(
+//		func() float64 { return c.Get()*9/5 + 32 },
+//		func(v float64) { c.Set((v - 32) * 5 / 9) })
+func DeriveWritable[T any](get func() T, set func(T)) *WritableDerived[T] {
+	return &WritableDerived[T]{Derived: Derive(get), set: set}
+}
+
+func (w *WritableDerived[T]) Set(v T) {
+	Batch(func() { w.set(v) })
+}
+
+var _ Writable[int] = (*WritableDerived[int])(nil)
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// === Effect ===
+
+type effect struct {
+	mu       sync.Mutex
+	fn       func() func()
+	cleanup  func()
+	id       uint64
+	level    int
+	stale    bool
+	disposed bool
+	// scheduled effects re-run through the current Scheduler.
+	scheduled bool
+
+	scope  *scope
+	cancel context.CancelFunc
+
+	runMu   sync.Mutex
+	deps    dependencies
+	updated lastUpdate
+}
+
+// Effect runs fn now and again whenever an Observable or Derived it read
+// during its last run changes. Dependencies are tracked the same way as in
+// Derive. If fn returns a cleanup func, it is called before the next run
+// and when the effect is disposed. The returned func disposes the effect;
+// an Effect created inside another computation is also disposed with it.
+func Effect(fn func() (cleanup func())) func() {
+	return newEffect(fn, false)
+}
+
+func newEffect(fn func() func(), scheduled bool) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &effect{
+		fn:        fn,
+		id:        nextNodeID(),
+		scope:     newScope(ctx),
+		cancel:    cancel,
+		scheduled: scheduled,
+	}
+	adopt(e.dispose)
+	e.runMu.Lock()
+	e.run()
+	e.unlockRun()
+	return e.dispose
+}
+
+// run calls the previous cleanup, then fn, and re-tracks its dependencies.
+// It must be called with runMu held.
+func (e *effect) run() {
+	e.mu.Lock()
+	cleanup := e.cleanup
+	e.cleanup = nil
+	e.mu.Unlock()
+	if cleanup != nil {
+		cleanup()
+	}
+
+	next, read, err := track(e.scope, e, e.fn)
+	e.updated.touch()
+	if err != nil {
+		reportError(e.scope, err)
+	}
+
+	e.mu.Lock()
+	if e.disposed {
+		e.mu.Unlock()
+		if next != nil {
+			next()
+		}
+		return
+	}
+	e.cleanup = next
+	e.mu.Unlock()
+
+	level := e.deps.reconcile(e, read)
+
+	e.mu.Lock()
+	e.level = level
+	e.mu.Unlock()
+}
+
+// unlockRun releases runMu, first detaching e from its sources if it was
+// disposed meanwhile.
+func (e *effect) unlockRun() {
+	e.mu.Lock()
+	disposed := e.disposed
+	e.mu.Unlock()
+	if disposed {
+		e.deps.release()
+	}
+	e.runMu.Unlock()
+}
+
+func (e *effect) markStale() {
+	e.mu.Lock()
+	if e.stale || e.disposed {
+		e.mu.Unlock()
+		return
+	}
+	e.stale = true
+	e.mu.Unlock()
+	enqueue(e)
+}
+
+func (e *effect) height() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.level
+}
+
+func (e *effect) nodeID() uint64 { return e.id }
+
+// flush re-runs the effect if one of its dependencies actually changed, or
+// schedules that check for a scheduled effect. e stays stale until then, so
+// further changes do not queue it again.
+func (e *effect) flush() {
+	if e.scheduled {
+		// A deferred run happens outside the flush, so it must take part in
+		// propagation on its own.
+		currentScheduler().Schedule(e, func() { Batch(e.update) })
+		return
+	}
+	e.update()
+}
+
+func (e *effect) update() {
+	e.runMu.Lock()
+	defer e.unlockRun()
+
+	e.mu.Lock()
+	stale := e.stale && !e.disposed
+	e.stale = false
+	e.mu.Unlock()
+	if stale && e.deps.changed() {
+		e.run()
+	}
+}
+
+// abandon drops a pending run of e, so the next change triggers it again.
+func (e *effect) abandon() {
+	e.mu.Lock()
+	e.stale = false
+	e.mu.Unlock()
+}
+
+func (e *effect) inspect() nodeInfo {
+	return nodeInfo{kind: "effect", updated: e.updated.time(), sources: e.deps.sources()}
+}
+
+func (e *effect) dispose() {
+	e.mu.Lock()
+	if e.disposed {
+		e.mu.Unlock()
+		return
+	}
+	e.disposed = true
+	cleanup := e.cleanup
+	e.cleanup = nil
+	e.mu.Unlock()
+
+	// As with Derived.Dispose, a run in progress detaches e when it ends.
+	if e.runMu.TryLock() {
+		e.unlockRun()
+	}
+
+	e.scope.disposeOwned()
+	e.cancel()
+	if cleanup != nil {
+		cleanup()
+	}
+}
@@ -0,0 +1,19 @@
+package core
+
+import "reflect"
+
+// === Equality ===
+
+// defaultEqual returns an equality func for T, or nil when values of T
+// cannot be compared with ==. Interface types are compared dynamically and
+// treated as unequal when the values they hold are not comparable.
+func defaultEqual[T any]() func(a, b T) bool {
+	t := reflect.TypeFor[T]()
+	if !t.Comparable() {
+		return nil
+	}
+	return func(a, b T) bool {
+		defer func() { recover() }()
+		return any(a) == any(b)
+	}
+}
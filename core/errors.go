@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// === Error handling ===
+//
+// Subscribers, Derive computations and Effects run from inside propagation,
+// where there is no caller to return an error to. A panic in one of them is
+// recovered and reported instead, so it cannot take down the rest of the
+// app: to the handler of the nearest enclosing Catch, or else to the
+// OnError handler.
+
+// PanicError is reported when a subscriber, computation or effect panics.
+type PanicError struct {
+	// Node names what panicked, as in CycleError.
+	Node  string
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("core: panic in %s: %v", e.Node, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+var errorHandler atomic.Pointer[func(error)]
+
+// OnError sets the handler for errors reported outside of any Catch:
+// recovered panics and CycleErrors. The default handler logs them; a nil
+// fn restores it.
+func OnError(fn func(err error)) {
+	if fn == nil {
+		errorHandler.Store(nil)
+		return
+	}
+	errorHandler.Store(&fn)
+}
+
+// Catch runs fn as an error boundary. Errors from the computations, effects
+// and subscriptions created while fn runs, and a panic in fn itself, are
+// passed to handler instead of the OnError handler. Computations created
+// during fn are owned by the boundary and disposed by the returned func.
+func Catch(handler func(err error), fn func()) (dispose func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newScope(ctx)
+	s.onError = handler
+	dispose = func() {
+		s.disposeOwned()
+		cancel()
+	}
+	adopt(dispose)
+
+	withScope(s, func() {
+		defer recoverTo(s, "Catch")
+		fn()
+	})
+	return dispose
+}
+
+// reportError passes err to the handler of the nearest boundary above s, or
+// to the OnError handler.
+func reportError(s *scope, err error) {
+	for ; s != nil; s = s.parent {
+		if s.onError != nil {
+			s.onError(err)
+			return
+		}
+	}
+	if fn := errorHandler.Load(); fn != nil {
+		(*fn)(err)
+		return
+	}
+	log.Println(err)
+}
+
+// recoverTo reports a panic in node to the boundary above s. It must be
+// deferred directly.
+func recoverTo(s *scope, node any) {
+	if r := recover(); r != nil {
+		reportError(s, newPanicError(node, r))
+	}
+}
+
+func newPanicError(node any, value any) *PanicError {
+	name, ok := node.(string)
+	if !ok {
+		name = nodeLabel(node)
+	}
+	return &PanicError{Node: name, Value: value, Stack: debug.Stack()}
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// catchAndRelease builds body inside a Catch whose handler disposes the
+// boundary, as ErrorBoundary does, and returns the reported errors.
+func catchAndRelease(t *testing.T, trigger func(), body func()) []error {
+	t.Helper()
+	var errs []error
+	var release func()
+	release = Catch(func(err error) {
+		errs = append(errs, err)
+		release()
+	}, body)
+
+	done := make(chan struct{})
+	go func() {
+		trigger()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("disposing the boundary from its handler deadlocked")
+	}
+	return errs
+}
+
+func TestCatchReleaseOnDerivePanic(t *testing.T) {
+	x := NewObservable(0)
+	var d *Derived[int]
+	errs := catchAndRelease(t, func() { x.Set(1) }, func() {
+		d = Derive(func() int {
+			if x.Get() == 1 {
+				panic("boom")
+			}
+			return 0
+		})
+	})
+
+	var perr *PanicError
+	if len(errs) != 1 || !errors.As(errs[0], &perr) {
+		t.Fatalf("errors = %v, want one PanicError", errs)
+	}
+	x.Set(2)
+	if got := d.Get(); got != 0 {
+		t.Fatalf("disposed Derived recomputed to %d", got)
+	}
+}
+
+func TestCatchReleaseOnEffectPanic(t *testing.T) {
+	x := NewObservable(0)
+	runs := 0
+	errs := catchAndRelease(t, func() { x.Set(1) }, func() {
+		Effect(func() func() {
+			runs++
+			if x.Get() == 1 {
+				panic("boom")
+			}
+			return nil
+		})
+	})
+
+	if len(errs) != 1 {
+		t.Fatalf("errors = %v, want one", errs)
+	}
+	x.Set(2)
+	if runs != 2 {
+		t.Fatalf("runs = %d, want 2: a disposed Effect ran again", runs)
+	}
+}
+
+func TestCatchReleaseOnNestedDerivePanic(t *testing.T) {
+	x := NewObservable(0)
+	var outer *Derived[int]
+	errs := catchAndRelease(t, func() { x.Set(1) }, func() {
+		inner := Derive(func() int {
+			if x.Get() == 1 {
+				panic("boom")
+			}
+			return x.Get()
+		})
+		outer = Derive(func() int { return inner.Get() + 1 })
+	})
+
+	if len(errs) != 1 {
+		t.Fatalf("errors = %v, want one", errs)
+	}
+	x.Set(2)
+	if got := outer.Get(); got != 1 {
+		t.Fatalf("disposed Derived recomputed to %d", got)
+	}
+}
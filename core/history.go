@@ -0,0 +1,376 @@
+package core
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// === History (undo/redo) ===
+
+// HistoryOption configures a History or UndoGroup.
+type HistoryOption func(*historyConfig)
+
+type historyConfig struct {
+	depth    int
+	coalesce time.Duration
+	clock    Clock
+	group    *UndoGroup
+}
+
+// WithDepth keeps at most n undo steps; older ones are dropped. The default
+// of 0 keeps everything.
+func WithDepth(n int) HistoryOption {
+	return func(cfg *historyConfig) { cfg.depth = n }
+}
+
+// WithCoalesce merges consecutive edits of the same observable made less
+// than window apart into one undo step, so typing a word undoes as a unit.
+func WithCoalesce(window time.Duration) HistoryOption {
+	return func(cfg *historyConfig) { cfg.coalesce = window }
+}
+
+// WithHistoryClock makes coalescing use c instead of SystemClock.
+func WithHistoryClock(c Clock) HistoryOption {
+	return func(cfg *historyConfig) { cfg.clock = c }
+}
+
+// InGroup records a History into g, so that its changes share one undo
+// stack with every other History in the group.
+func InGroup(g *UndoGroup) HistoryOption {
+	return func(cfg *historyConfig) { cfg.group = g }
+}
+
+func newHistoryConfig(opts []HistoryOption) historyConfig {
+	cfg := historyConfig{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// historyChange is one recorded edit of one observable.
+type historyChange struct {
+	owner      any
+	undo, redo func()
+}
+
+// historyEntry is one undo step.
+type historyEntry struct {
+	changes []historyChange
+	at      time.Time
+}
+
+// UndoGroup is an undo stack shared by several histories. Each Undo or Redo
+// reverts or reapplies one step, which may span several observables when
+// they were changed inside Transaction.
+type UndoGroup struct {
+	mu      sync.Mutex
+	cfg     historyConfig
+	undo    []*historyEntry
+	redo    []*historyEntry
+	members []historyMember
+	txDepth int
+	// sealed stops the next change from coalescing into the last step.
+	sealed  bool
+	canUndo *Observable[bool]
+	canRedo *Observable[bool]
+}
+
+func NewUndoGroup(opts ...HistoryOption) *UndoGroup {
+	return &UndoGroup{
+		cfg:     newHistoryConfig(opts),
+		canUndo: NewObservable(false),
+		canRedo: NewObservable(false),
+	}
+}
+
+func (g *UndoGroup) CanUndo() ReadonlyObservable[bool] { return g.canUndo }
+func (g *UndoGroup) CanRedo() ReadonlyObservable[bool] { return g.canRedo }
+
+// historyMember is a History that belongs to a group.
+type historyMember interface {
+	// begin starts a transaction. It returns the change made since the
+	// member last recorded, if any, and a func that returns the change made
+	// during the transaction.
+	begin() (pending *historyChange, end func() *historyChange)
+}
+
+func (g *UndoGroup) add(m historyMember) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.members = append(g.members, m)
+}
+
+func (g *UndoGroup) remove(m historyMember) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.members = slices.DeleteFunc(slices.Clone(g.members), func(x historyMember) bool { return x == m })
+}
+
+// Transaction runs fn and records every change made inside it as a single
+// undo step. Observers are notified once, when fn returns. Changes are
+// captured by comparing every history in the group before and after fn, so
+// this holds even when fn runs inside a Batch or a subscriber, where
+// notifications are delivered later.
+func (g *UndoGroup) Transaction(fn func()) {
+	g.mu.Lock()
+	if g.txDepth > 0 {
+		g.txDepth++
+		g.mu.Unlock()
+		defer func() {
+			g.mu.Lock()
+			g.txDepth--
+			g.mu.Unlock()
+		}()
+		Batch(fn)
+		return
+	}
+	members := g.members
+	g.mu.Unlock()
+
+	ends := make([]func() *historyChange, len(members))
+	for i, m := range members {
+		var pending *historyChange
+		pending, ends[i] = m.begin()
+		if pending != nil {
+			g.record(*pending)
+		}
+	}
+
+	g.mu.Lock()
+	g.txDepth++
+	g.mu.Unlock()
+
+	defer func() {
+		entry := &historyEntry{at: g.cfg.clock.Now()}
+		for _, end := range ends {
+			if c := end(); c != nil {
+				entry.changes = append(entry.changes, *c)
+			}
+		}
+		g.mu.Lock()
+		g.txDepth--
+		if len(entry.changes) > 0 {
+			g.redo = nil
+			g.push(entry)
+			g.sealed = true
+		}
+		g.mu.Unlock()
+		g.publish()
+	}()
+
+	Batch(fn)
+}
+
+// Undo reverts the most recent step, if any.
+func (g *UndoGroup) Undo() {
+	g.mu.Lock()
+	if len(g.undo) == 0 {
+		g.mu.Unlock()
+		return
+	}
+	entry := g.undo[len(g.undo)-1]
+	g.undo = g.undo[:len(g.undo)-1]
+	g.redo = append(g.redo, entry)
+	g.sealed = true
+	g.mu.Unlock()
+
+	Batch(func() {
+		for i := len(entry.changes) - 1; i >= 0; i-- {
+			entry.changes[i].undo()
+		}
+	})
+	g.publish()
+}
+
+// Redo reapplies the most recently undone step, if any.
+func (g *UndoGroup) Redo() {
+	g.mu.Lock()
+	if len(g.redo) == 0 {
+		g.mu.Unlock()
+		return
+	}
+	entry := g.redo[len(g.redo)-1]
+	g.redo = g.redo[:len(g.redo)-1]
+	g.undo = append(g.undo, entry)
+	g.sealed = true
+	g.mu.Unlock()
+
+	Batch(func() {
+		for _, c := range entry.changes {
+			c.redo()
+		}
+	})
+	g.publish()
+}
+
+// Clear forgets every recorded step.
+func (g *UndoGroup) Clear() {
+	g.mu.Lock()
+	g.undo, g.redo = nil, nil
+	g.mu.Unlock()
+	g.publish()
+}
+
+// record adds c as a new step, or merges it into the previous step when
+// coalescing applies. Changes made during a transaction are left to it.
+func (g *UndoGroup) record(c historyChange) {
+	now := g.cfg.clock.Now()
+
+	g.mu.Lock()
+	if g.txDepth > 0 {
+		g.mu.Unlock()
+		return
+	}
+	g.redo = nil
+	switch {
+	case g.canCoalesce(c, now):
+		last := g.undo[len(g.undo)-1]
+		last.changes[0].redo = c.redo
+		last.at = now
+	default:
+		g.push(&historyEntry{changes: []historyChange{c}, at: now})
+		g.sealed = false
+	}
+	g.mu.Unlock()
+	g.publish()
+}
+
+// canCoalesce must be called with g.mu held.
+func (g *UndoGroup) canCoalesce(c historyChange, now time.Time) bool {
+	if g.cfg.coalesce <= 0 || g.sealed || len(g.undo) == 0 {
+		return false
+	}
+	last := g.undo[len(g.undo)-1]
+	return len(last.changes) == 1 && last.changes[0].owner == c.owner && now.Sub(last.at) < g.cfg.coalesce
+}
+
+// push must be called with g.mu held.
+func (g *UndoGroup) push(entry *historyEntry) {
+	g.undo = append(g.undo, entry)
+	if g.cfg.depth > 0 && len(g.undo) > g.cfg.depth {
+		g.undo = g.undo[len(g.undo)-g.cfg.depth:]
+	}
+}
+
+func (g *UndoGroup) publish() {
+	g.mu.Lock()
+	canUndo, canRedo := len(g.undo) > 0, len(g.redo) > 0
+	g.mu.Unlock()
+	Batch(func() {
+		g.canUndo.Set(canUndo)
+		g.canRedo.Set(canRedo)
+	})
+}
+
+// History records the changes of an Observable so they can be undone and
+// redone. Unless created with InGroup, it has an undo stack of its own.
+type History[T any] struct {
+	obs   *Observable[T]
+	group *UndoGroup
+	stop  func()
+
+	// prev is the value as of the last recorded or applied change, and
+	// prevVer the version of obs it was read at.
+	mu       sync.Mutex
+	prev     T
+	prevVer  uint64
+	applying bool
+}
+
+// NewHistory starts recording changes made to obs.
+func NewHistory[T any](obs *Observable[T], opts ...HistoryOption) *History[T] {
+	cfg := newHistoryConfig(opts)
+	group := cfg.group
+	if group == nil {
+		group = NewUndoGroup(opts...)
+	}
+
+	h := &History[T]{obs: obs, group: group}
+	h.prev, h.prevVer = obs.snapshot()
+	h.stop = changes[T](obs, h.observe)
+	group.add(h)
+	return h
+}
+
+func (h *History[T]) observe(v T) {
+	ver := h.obs.version()
+
+	h.mu.Lock()
+	if ver == h.prevVer {
+		h.mu.Unlock()
+		return
+	}
+	old := h.prev
+	h.prev, h.prevVer = v, ver
+	applying := h.applying
+	h.mu.Unlock()
+
+	if !applying {
+		h.group.record(h.change(old, v))
+	}
+}
+
+func (h *History[T]) change(from, to T) historyChange {
+	return historyChange{
+		owner: h,
+		undo:  func() { h.apply(from) },
+		redo:  func() { h.apply(to) },
+	}
+}
+
+func (h *History[T]) begin() (*historyChange, func() *historyChange) {
+	before, beforeVer := h.obs.snapshot()
+
+	var pending *historyChange
+	h.mu.Lock()
+	if beforeVer != h.prevVer && !h.applying {
+		c := h.change(h.prev, before)
+		pending = &c
+	}
+	h.prev, h.prevVer = before, beforeVer
+	h.mu.Unlock()
+
+	return pending, func() *historyChange {
+		after, afterVer := h.obs.snapshot()
+		if afterVer == beforeVer {
+			return nil
+		}
+		h.mu.Lock()
+		h.prev, h.prevVer = after, afterVer
+		h.mu.Unlock()
+		c := h.change(before, after)
+		return &c
+	}
+}
+
+// apply sets v without recording it.
+func (h *History[T]) apply(v T) {
+	h.mu.Lock()
+	h.applying = true
+	h.mu.Unlock()
+
+	h.obs.Set(v)
+
+	h.mu.Lock()
+	h.applying = false
+	h.prev, h.prevVer = h.obs.snapshot()
+	h.mu.Unlock()
+}
+
+func (h *History[T]) Undo() { h.group.Undo() }
+func (h *History[T]) Redo() { h.group.Redo() }
+
+func (h *History[T]) CanUndo() ReadonlyObservable[bool] { return h.group.CanUndo() }
+func (h *History[T]) CanRedo() ReadonlyObservable[bool] { return h.group.CanRedo() }
+
+// Transaction records every change made inside fn, to any observable in
+// the same group, as a single undo step.
+func (h *History[T]) Transaction(fn func()) { h.group.Transaction(fn) }
+
+// Dispose stops recording. Steps already recorded stay undoable.
+func (h *History[T]) Dispose() {
+	h.stop()
+	h.group.remove(h)
+}
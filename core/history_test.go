@@ -0,0 +1,85 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistoryUndoRedo(t *testing.T) {
+	text := NewObservable("")
+	h := NewHistory(text)
+
+	text.Set("a")
+	text.Set("ab")
+	h.Undo()
+	if got := text.Get(); got != "a" {
+		t.Fatalf("after undo = %q, want a", got)
+	}
+	h.Redo()
+	if got := text.Get(); got != "ab" {
+		t.Fatalf("after redo = %q, want ab", got)
+	}
+	if !h.CanUndo().Get() || h.CanRedo().Get() {
+		t.Fatalf("CanUndo = %v, CanRedo = %v", h.CanUndo().Get(), h.CanRedo().Get())
+	}
+}
+
+func TestHistoryCoalesce(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	text := NewObservable("")
+	h := NewHistory(text, WithCoalesce(500*time.Millisecond), WithHistoryClock(clock))
+
+	text.Set("a")
+	clock.Advance(100 * time.Millisecond)
+	text.Set("ab")
+	clock.Advance(time.Second)
+	text.Set("abc")
+
+	h.Undo()
+	if got := text.Get(); got != "ab" {
+		t.Fatalf("after first undo = %q, want ab", got)
+	}
+	h.Undo()
+	if got := text.Get(); got != "" {
+		t.Fatalf("after second undo = %q, want empty", got)
+	}
+}
+
+func TestUndoGroupTransaction(t *testing.T) {
+	cases := map[string]func(func()){
+		"direct":       func(fn func()) { fn() },
+		"inside Batch": Batch,
+		"from subscriber": func(fn func()) {
+			trigger := NewObservable(0)
+			stop := changes[int](trigger, func(int) { fn() })
+			defer stop()
+			trigger.Set(1)
+		},
+	}
+	for name, run := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := NewUndoGroup()
+			p, q := NewObservable(0), NewObservable(0)
+			NewHistory(p, InGroup(g))
+			NewHistory(q, InGroup(g))
+
+			run(func() {
+				g.Transaction(func() {
+					p.Set(1)
+					q.Set(1)
+				})
+			})
+			g.Undo()
+			if p.Get() != 0 || q.Get() != 0 {
+				t.Fatalf("after undo p=%d q=%d, want 0 0", p.Get(), q.Get())
+			}
+			if g.CanUndo().Get() {
+				t.Fatal("transaction recorded as more than one step")
+			}
+			g.Redo()
+			if p.Get() != 1 || q.Get() != 1 {
+				t.Fatalf("after redo p=%d q=%d, want 1 1", p.Get(), q.Get())
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+// === Lens ===
+
+// FieldLens is a writable view onto one part of a parent observable.
+type FieldLens[S, F any] struct {
+	*Derived[F]
+	parent Writable[S]
+	get    func(S) F
+	set    func(S, F) S
+}
+
+// Lens focuses on the part of parent selected by get. Reading it tracks the
+// parent, but subscribers are only notified when the focused value itself
+// changes (for comparable F). Set stores set(current, v) back into parent,
+// so set must return an updated copy rather than modify its argument:
+//
+//	name := core.Lens(user,
+//		func(u User) string { return u.Name },
+//		func(u User, name string) User { u.Name = name; return u })
+//
+// set runs with no lock held, so it may read parent or anything derived from
+// it; if parent changes concurrently, it is called again on the new value.
+// Lenses can be stacked by passing one as the parent of another.
+func Lens[S, F any](parent Writable[S], get func(S) F, set func(S, F) S) *FieldLens[S, F] {
+	return &FieldLens[S, F]{
+		Derived: Derive(func() F { return get(parent.Get()) }),
+		parent:  parent,
+		get:     get,
+		set:     set,
+	}
+}
+
+// Set writes v into the focused part of the parent.
+func (l *FieldLens[S, F]) Set(v F) {
+	updateWritable(l.parent, func(s S) S { return l.set(s, v) })
+}
+
+func (l *FieldLens[S, F]) update(fn func(F) F) {
+	updateWritable(l.parent, func(s S) S {
+		return l.set(s, fn(l.get(s)))
+	})
+}
+
+// updateWritable applies fn to the current value of w, atomically when w
+// supports it.
+func updateWritable[T any](w Writable[T], fn func(T) T) {
+	if u, ok := w.(interface{ update(func(T) T) }); ok {
+		u.update(fn)
+		return
+	}
+	w.Set(fn(Untrack(w.Get)))
+}
+
+var _ Writable[int] = (*FieldLens[struct{}, int])(nil)
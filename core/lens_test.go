@@ -0,0 +1,78 @@
+package core
+
+import "testing"
+
+type address struct{ City string }
+
+type user struct {
+	Name    string
+	Email   string
+	Address address
+}
+
+func nameLens(u Writable[user]) *FieldLens[user, string] {
+	return Lens(u,
+		func(u user) string { return u.Name },
+		func(u user, name string) user { u.Name = name; return u })
+}
+
+func TestLensWritesParent(t *testing.T) {
+	u := NewObservable(user{Name: "ann"})
+	name := nameLens(u)
+
+	name.Set("bob")
+	if got := u.Get().Name; got != "bob" {
+		t.Fatalf("parent name = %q, want bob", got)
+	}
+	if got := name.Get(); got != "bob" {
+		t.Fatalf("lens = %q, want bob", got)
+	}
+}
+
+func TestLensNotifiesOnlyForItsField(t *testing.T) {
+	u := NewObservable(user{Name: "ann"})
+	name := nameLens(u)
+	calls := 0
+	name.Subscribe(func(string) { calls++ })
+
+	u.Set(user{Name: "ann", Email: "ann@example.com"})
+	if calls != 1 {
+		t.Fatalf("calls = %d after unrelated change, want 1", calls)
+	}
+	name.Set("bob")
+	if calls != 2 {
+		t.Fatalf("calls = %d after field change, want 2", calls)
+	}
+}
+
+func TestLensStacked(t *testing.T) {
+	u := NewObservable(user{})
+	addr := Lens(u,
+		func(u user) address { return u.Address },
+		func(u user, a address) user { u.Address = a; return u })
+	city := Lens(addr,
+		func(a address) string { return a.City },
+		func(a address, c string) address { a.City = c; return a })
+
+	city.Set("Paris")
+	if got := u.Get().Address.City; got != "Paris" {
+		t.Fatalf("city = %q, want Paris", got)
+	}
+}
+
+// A setter that reads the parent, or a Derived over it, must not deadlock.
+func TestLensSetterReadsParent(t *testing.T) {
+	u := NewObservable(user{Email: "x"})
+	email := Derive(func() string { return u.Get().Email })
+	name := Lens(u,
+		func(u user) string { return u.Name },
+		func(v user, name string) user {
+			v.Name = name + "<" + u.Get().Email + email.Get() + ">"
+			return v
+		})
+
+	name.Set("ann")
+	if got := u.Get().Name; got != "ann<xx>" {
+		t.Fatalf("name = %q, want ann<xx>", got)
+	}
+}
@@ -0,0 +1,305 @@
+package core
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// === ObservableMap ===
+
+// MapOp identifies the kind of edit described by a MapChange.
+type MapOp int
+
+const (
+	MapAdded MapOp = iota
+	MapRemoved
+	MapChanged
+)
+
+// MapChange describes a single edit to an ObservableMap. For MapChanged and
+// MapRemoved, Old holds the previous value.
+type MapChange[K comparable, V any] struct {
+	Op    MapOp
+	Key   K
+	Value V
+	Old   V
+}
+
+// mapSlot is the per-key state behind ObservableMap.Key.
+type mapSlot[V any] struct {
+	Value V
+	OK    bool
+}
+
+// ObservableMap is a reactive keyed store. Reads are tracked per key, so a
+// Derive that reads one key does not recompute when another key changes.
+// Keys, Len and Has track the key set; All tracks every change.
+type ObservableMap[K comparable, V any] struct {
+	mu        sync.Mutex
+	values    map[K]V
+	order     []K
+	slots     map[K]*Observable[mapSlot[V]]
+	keys      *Observable[int]
+	keysVer   int
+	equal     func(a, b V) bool
+	pending   []MapChange[K, V]
+	listeners []listener[map[K]V]
+	watchers  []listener[[]MapChange[K, V]]
+	observers observerSet
+	nextID    int
+	id        uint64
+	ver       uint64
+	updated   lastUpdate
+}
+
+func NewObservableMap[K comparable, V any]() *ObservableMap[K, V] {
+	return &ObservableMap[K, V]{
+		values: map[K]V{},
+		slots:  map[K]*Observable[mapSlot[V]]{},
+		keys:   NewObservableWithEqual(0, nil),
+		equal:  defaultEqual[V](),
+		id:     nextNodeID(),
+	}
+}
+
+// Key returns a read-only observable for the value stored under k. It holds
+// the zero value while k is absent and follows k as it is added, changed or
+// removed.
+func (m *ObservableMap[K, V]) Key(k K) ReadonlyObservable[V] {
+	return keyView[K, V]{m, k}
+}
+
+// Get returns the value stored under k and whether it is present. Only k is
+// tracked.
+func (m *ObservableMap[K, V]) Get(k K) (V, bool) {
+	if !tracking() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		v, ok := m.values[k]
+		return v, ok
+	}
+	s := m.slot(k).Get()
+	return s.Value, s.OK
+}
+
+func (m *ObservableMap[K, V]) Has(k K) bool {
+	_, ok := m.Get(k)
+	return ok
+}
+
+// Keys returns the keys in insertion order.
+func (m *ObservableMap[K, V]) Keys() []K {
+	m.keys.Get()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.order)
+}
+
+func (m *ObservableMap[K, V]) Len() int {
+	m.keys.Get()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.values)
+}
+
+// All returns a copy of the whole map and tracks every change to it.
+func (m *ObservableMap[K, V]) All() map[K]V {
+	trackObservable(m)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.values)
+}
+
+// Set stores v under k. Storing a value equal to the current one is a no-op.
+func (m *ObservableMap[K, V]) Set(k K, v V) {
+	startBatch()
+	defer endBatch()
+
+	m.mu.Lock()
+	old, existed := m.values[k]
+	if existed && m.equal != nil && m.equal(old, v) {
+		m.mu.Unlock()
+		return
+	}
+	m.values[k] = v
+	change := MapChange[K, V]{Op: MapChanged, Key: k, Value: v, Old: old}
+	if !existed {
+		m.order = append(m.order, k)
+		change = MapChange[K, V]{Op: MapAdded, Key: k, Value: v}
+	}
+	m.commit(change, !existed)
+}
+
+func (m *ObservableMap[K, V]) Delete(k K) {
+	startBatch()
+	defer endBatch()
+
+	m.mu.Lock()
+	old, existed := m.values[k]
+	if !existed {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.values, k)
+	m.order = slices.DeleteFunc(m.order, func(key K) bool { return key == k })
+	m.commit(MapChange[K, V]{Op: MapRemoved, Key: k, Old: old}, true)
+}
+
+// commit records change and propagates it. It must be called inside a
+// batch with m.mu held, and releases it.
+func (m *ObservableMap[K, V]) commit(change MapChange[K, V], keysChanged bool) {
+	m.pending = append(m.pending, change)
+	slot := m.slots[change.Key]
+	if keysChanged {
+		m.keysVer++
+	}
+	keysVer := m.keysVer
+	m.ver++
+	m.mu.Unlock()
+	m.updated.touch()
+
+	if slot != nil {
+		slot.Set(mapSlot[V]{Value: change.Value, OK: change.Op != MapRemoved})
+		if change.Op == MapRemoved {
+			m.dropSlot(change.Key, slot)
+		}
+	}
+	if keysChanged {
+		m.keys.Set(keysVer)
+	}
+	m.observers.markStale()
+	enqueue(m)
+}
+
+// slot returns the observable tracking k, creating it if needed. Slots are
+// only created for tracked reads and subscriptions, and dropped again by
+// dropSlot, so reading many absent keys does not grow the map.
+func (m *ObservableMap[K, V]) slot(k K) *Observable[mapSlot[V]] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.slots[k]
+	if !ok {
+		v, present := m.values[k]
+		s = NewObservableWithEqual(mapSlot[V]{Value: v, OK: present}, m.slotEqual)
+		m.slots[k] = s
+	}
+	return s
+}
+
+// dropSlot forgets s, the slot of the absent key k, unless something still
+// depends on or listens to it. It must be called inside a batch, which keeps
+// keyView.Subscribe from attaching to s meanwhile.
+func (m *ObservableMap[K, V]) dropSlot(k K, s *Observable[mapSlot[V]]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, present := m.values[k]; !present && m.slots[k] == s && !s.observed() {
+		delete(m.slots, k)
+	}
+}
+
+func (m *ObservableMap[K, V]) slotEqual(a, b mapSlot[V]) bool {
+	return a.OK == b.OK && m.equal != nil && m.equal(a.Value, b.Value)
+}
+
+// Subscribe calls fn with a copy of the map now and after every change.
+func (m *ObservableMap[K, V]) Subscribe(fn func(map[K]V)) func() {
+	l := newListener(0, fn)
+	m.mu.Lock()
+	m.nextID++
+	l.id = m.nextID
+	m.listeners = append(m.listeners, l)
+	values := maps.Clone(m.values)
+	m.mu.Unlock()
+	l.call(m, values)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.listeners = removeByID(m.listeners, l.id)
+	}
+}
+
+// Observe calls fn with the added, removed and changed entries since the
+// last propagation, in the order they happened.
+func (m *ObservableMap[K, V]) Observe(fn func([]MapChange[K, V])) func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	id := m.nextID
+	m.watchers = append(m.watchers, newListener(id, fn))
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.watchers = removeByID(m.watchers, id)
+	}
+}
+
+func (m *ObservableMap[K, V]) addObserver(obs observer) func() { return m.observers.add(obs) }
+
+func (m *ObservableMap[K, V]) height() int    { return 0 }
+func (m *ObservableMap[K, V]) nodeID() uint64 { return m.id }
+
+func (m *ObservableMap[K, V]) version() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ver
+}
+
+func (m *ObservableMap[K, V]) flush() {
+	m.mu.Lock()
+	changes := m.pending
+	m.pending = nil
+	values := maps.Clone(m.values)
+	listeners, watchers := m.listeners, m.watchers
+	m.mu.Unlock()
+
+	for _, w := range watchers {
+		w.call(m, changes)
+	}
+	for _, l := range listeners {
+		l.call(m, values)
+	}
+}
+
+// Named gives m a debug name and registers it for DumpGraph.
+func (m *ObservableMap[K, V]) Named(name string) *ObservableMap[K, V] {
+	register(m, name)
+	return m
+}
+
+func (m *ObservableMap[K, V]) inspect() nodeInfo {
+	m.mu.Lock()
+	subscribers := len(m.listeners) + len(m.watchers)
+	m.mu.Unlock()
+	return nodeInfo{
+		kind:        "map",
+		subscribers: subscribers,
+		updated:     m.updated.time(),
+		observers:   m.observers.snapshot(),
+	}
+}
+
+// keyView exposes a map slot as a read-only observable of its value.
+// The slot is looked up on every use, since it may have been dropped.
+type keyView[K comparable, V any] struct {
+	m *ObservableMap[K, V]
+	k K
+}
+
+func (v keyView[K, V]) Get() V {
+	value, _ := v.m.Get(v.k)
+	return value
+}
+
+func (v keyView[K, V]) Subscribe(fn func(V)) func() {
+	var slot *Observable[mapSlot[V]]
+	var stop func()
+	Batch(func() {
+		slot = v.m.slot(v.k)
+		stop = slot.Subscribe(func(s mapSlot[V]) { fn(s.Value) })
+	})
+	return func() {
+		stop()
+		Batch(func() { v.m.dropSlot(v.k, slot) })
+	}
+}
@@ -0,0 +1,63 @@
+package core
+
+import "testing"
+
+func TestMapUntrackedReadsCreateNoSlots(t *testing.T) {
+	m := NewObservableMap[string, int]()
+	for _, k := range []string{"a", "b", "c"} {
+		if m.Has(k) {
+			t.Fatalf("Has(%q) on empty map", k)
+		}
+	}
+	m.Key("d").Get()
+	if n := len(m.slots); n != 0 {
+		t.Fatalf("%d slots after untracked reads, want 0", n)
+	}
+}
+
+func TestMapDeleteDropsUnobservedSlot(t *testing.T) {
+	m := NewObservableMap[string, int]()
+	m.Set("a", 1)
+	d := Derive(func() int {
+		v, _ := m.Get("a")
+		return v
+	})
+	if len(m.slots) != 1 {
+		t.Fatalf("tracked read created %d slots, want 1", len(m.slots))
+	}
+
+	m.Delete("a")
+	if len(m.slots) != 1 || d.Get() != 0 {
+		t.Fatalf("slot dropped while observed (slots=%d, d=%d)", len(m.slots), d.Get())
+	}
+
+	d.Dispose()
+	m.Set("a", 2)
+	m.Delete("a")
+	if len(m.slots) != 0 {
+		t.Fatalf("%d slots after deleting an unobserved key, want 0", len(m.slots))
+	}
+}
+
+func TestMapKeyFollowsKeyAcrossDelete(t *testing.T) {
+	m := NewObservableMap[string, int]()
+	key := m.Key("a")
+
+	var seen []int
+	stop := key.Subscribe(func(v int) { seen = append(seen, v) })
+	m.Set("a", 1)
+	m.Delete("a")
+	m.Set("a", 2)
+	stop()
+
+	if want := []int{0, 1, 0, 2}; len(seen) != len(want) || seen[1] != 1 || seen[3] != 2 {
+		t.Fatalf("seen = %v, want %v", seen, want)
+	}
+	m.Delete("a")
+	if len(m.slots) != 0 {
+		t.Fatalf("%d slots after unsubscribing and deleting, want 0", len(m.slots))
+	}
+	if got := key.Get(); got != 0 {
+		t.Fatalf("Key after delete = %d, want 0", got)
+	}
+}
@@ -6,22 +6,84 @@ import (
 )
 
 type internalObservable interface {
-	addSubscriber(func())
+	// addObserver registers o to be marked stale on change and returns a
+	// func that removes it again.
+	addObserver(o observer) func()
+	height() int
+	// version increases every time the value changes.
+	version() uint64
+}
+
+type listener[T any] struct {
+	id int
+	fn func(T)
+	// scope is where a panic in fn is reported.
+	scope *scope
+}
+
+func newListener[T any](id int, fn func(T)) listener[T] {
+	return listener[T]{id: id, fn: fn, scope: currentScope()}
+}
+
+// call runs l with v on behalf of node, reporting a panic instead of
+// letting it escape.
+func (l listener[T]) call(node any, v T) {
+	defer func() {
+		if r := recover(); r != nil {
+			reportError(l.scope, newPanicError("subscriber of "+nodeLabel(node), r))
+		}
+	}()
+	l.fn(v)
+}
+
+// removeByID returns ls without the listener registered under id. It never
+// modifies the backing array, so copies taken for notification stay valid.
+func removeByID[T any](ls []listener[T], id int) []listener[T] {
+	for i, l := range ls {
+		if l.id == id {
+			return append(ls[:i:i], ls[i+1:]...)
+		}
+	}
+	return ls
 }
 
 type Observable[T any] struct {
 	value     T
-	listeners []func(T)
+	listeners []listener[T]
+	observers observerSet
+	nextID    int
+	id        uint64
+	ver       uint64
+	equal     func(a, b T) bool
+	updated   lastUpdate
 	mu        sync.Mutex
 }
 
 type ReadonlyObservable[T any] interface {
 	Get() T
-	Subscribe(func(T))
+	// Subscribe calls fn with the current value and on every change. The
+	// returned func removes the subscription.
+	Subscribe(fn func(T)) func()
 }
 
+// Writable is an observable that can also be set. Two-way bindings such as
+// TextField.BindTo accept any Writable, so they work with an Observable as
+// well as with a Lens onto part of one.
+type Writable[T any] interface {
+	ReadonlyObservable[T]
+	Set(v T)
+}
+
+// NewObservable creates an observable holding initial. If T is comparable,
+// setting a value equal (==) to the current one is a no-op.
 func NewObservable[T any](initial T) *Observable[T] {
-	return &Observable[T]{value: initial}
+	return NewObservableWithEqual(initial, defaultEqual[T]())
+}
+
+// NewObservableWithEqual creates an observable that uses equal to decide
+// whether a Set changes its value. A nil equal notifies on every Set.
+func NewObservableWithEqual[T any](initial T, equal func(a, b T) bool) *Observable[T] {
+	return &Observable[T]{value: initial, id: nextNodeID(), equal: equal}
 }
 
 func (o *Observable[T]) Get() T {
@@ -31,26 +93,131 @@ func (o *Observable[T]) Get() T {
 	return o.value
 }
 
+// Set stores v and propagates the change. Once Set returns, every dependent
+// Derived and subscriber has seen v, unless Set was called inside a Batch or
+// from a subscriber, in which case propagation completes when the
+// surrounding flush does. While another goroutine is propagating, Set waits
+// for it to finish first. Setting a value equal to the current one does
+// nothing.
 func (o *Observable[T]) Set(v T) {
-	o.mu.Lock()
-	o.value = v
-	for _, listener := range o.listeners {
-		listener(v)
+	o.update(func(T) T { return v })
+}
+
+// update replaces the value with fn applied to the current one, atomically
+// with respect to other updates, and propagates the change like Set. fn and
+// the equality func run without o.mu held, so they may read o or panic; if
+// another update lands meanwhile, fn is called again on the newer value.
+func (o *Observable[T]) update(fn func(T) T) {
+	startBatch()
+	defer endBatch()
+
+	for {
+		o.mu.Lock()
+		cur, ver := o.value, o.ver
+		o.mu.Unlock()
+
+		v := fn(cur)
+		same := o.equal != nil && o.equal(cur, v)
+
+		o.mu.Lock()
+		if o.ver != ver {
+			o.mu.Unlock()
+			continue
+		}
+		if same {
+			o.mu.Unlock()
+			return
+		}
+		o.value = v
+		o.ver++
+		o.mu.Unlock()
+		break
 	}
+	o.updated.touch()
+
+	o.observers.markStale()
+	enqueue(o)
+}
+
+// Subscribe calls fn with the current value and after every Set. The
+// returned func removes the subscription; calling it more than once is safe.
+func (o *Observable[T]) Subscribe(fn func(T)) func() {
+	l := newListener(0, fn)
+	o.mu.Lock()
+	l.id = o.addListener(l)
+	v := o.value
 	o.mu.Unlock()
+	l.call(o, v)
+	return func() { o.removeListener(l.id) }
 }
 
-func (o *Observable[T]) Subscribe(listener func(T)) {
+// snapshot returns the value and its version without tracking.
+func (o *Observable[T]) snapshot() (T, uint64) {
 	o.mu.Lock()
-	o.listeners = append(o.listeners, listener)
-	listener(o.value)
+	defer o.mu.Unlock()
+	return o.value, o.ver
+}
+
+// observed reports whether anything depends on or listens to o.
+func (o *Observable[T]) observed() bool {
+	o.mu.Lock()
+	listeners := len(o.listeners)
 	o.mu.Unlock()
+	return listeners > 0 || o.observers.len() > 0
+}
+
+func (o *Observable[T]) addObserver(obs observer) func() { return o.observers.add(obs) }
+
+func (o *Observable[T]) height() int    { return 0 }
+func (o *Observable[T]) nodeID() uint64 { return o.id }
+
+func (o *Observable[T]) version() uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.ver
 }
 
-func (o *Observable[T]) addSubscriber(update func()) {
+// flush notifies listeners with the current value.
+func (o *Observable[T]) flush() {
 	o.mu.Lock()
-	o.listeners = append(o.listeners, func(T) { update() })
+	v := o.value
+	listeners := o.listeners
 	o.mu.Unlock()
+	for _, l := range listeners {
+		l.call(o, v)
+	}
+}
+
+// addListener must be called with o.mu held.
+func (o *Observable[T]) addListener(l listener[T]) int {
+	o.nextID++
+	l.id = o.nextID
+	o.listeners = append(o.listeners, l)
+	return l.id
+}
+
+func (o *Observable[T]) removeListener(id int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.listeners = removeByID(o.listeners, id)
+}
+
+// Named gives o a debug name and registers it for DumpGraph.
+func (o *Observable[T]) Named(name string) *Observable[T] {
+	register(o, name)
+	return o
+}
+
+func (o *Observable[T]) inspect() nodeInfo {
+	o.mu.Lock()
+	subscribers := len(o.listeners)
+	o.mu.Unlock()
+	return nodeInfo{
+		kind:        "observable",
+		subscribers: subscribers,
+		updated:     o.updated.time(),
+		observers:   o.observers.snapshot(),
+	}
 }
 
 var _ reactive.ReadonlyObservable[any] = (*Observable[any])(nil)
@@ -0,0 +1,146 @@
+package core
+
+import "sync"
+
+// === Operators ===
+//
+// Map and CombineN are computed with Derive, so they are glitch-free and
+// recompute once per propagation. The other operators act on the sequence
+// of changes a source goes through and are driven by its subscription.
+// Every operator result has a Dispose method that detaches it from its
+// sources, and is owned by the computation it is created in, if any. The
+// initial reads of those driven by subscriptions are not tracked.
+
+// Pair holds two values produced together, as by Zip or Pairwise.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Map derives a new value from src. Use the Derived.Map method to chain a
+// transform that keeps the same type.
+func Map[T any, U any](src ReadonlyObservable[T], f func(T) U) *Derived[U] {
+	return Derive(func() U {
+		return f(src.Get())
+	})
+}
+
+// Combine2 derives a value from the latest values of a and b.
+func Combine2[A, B, R any](a ReadonlyObservable[A], b ReadonlyObservable[B], f func(A, B) R) *Derived[R] {
+	return Derive(func() R {
+		return f(a.Get(), b.Get())
+	})
+}
+
+// Combine3 derives a value from the latest values of a, b and c.
+func Combine3[A, B, C, R any](a ReadonlyObservable[A], b ReadonlyObservable[B], c ReadonlyObservable[C], f func(A, B, C) R) *Derived[R] {
+	return Derive(func() R {
+		return f(a.Get(), b.Get(), c.Get())
+	})
+}
+
+// Filter holds the latest value of src that satisfies keep. It starts with
+// the current value of src if that passes, and the zero value otherwise.
+func Filter[T any](src ReadonlyObservable[T], keep func(T) bool) *Readonly[T] {
+	initial := Untrack(func() T {
+		var initial T
+		if v := src.Get(); keep(v) {
+			initial = v
+		}
+		return initial
+	})
+	out := newReadonly(initial)
+	out.onDispose(changes(src, func(v T) {
+		if keep(v) {
+			out.set(v)
+		}
+	}))
+	return out
+}
+
+// Scan folds every value src takes, starting with its current one, into an
+// accumulator seeded with seed.
+func Scan[T, R any](src ReadonlyObservable[T], seed R, f func(acc R, v T) R) *Readonly[R] {
+	var mu sync.Mutex
+	acc := Untrack(func() R { return f(seed, src.Get()) })
+	out := newReadonlyWithEqual(acc, nil)
+	out.onDispose(changes(src, func(v T) {
+		mu.Lock()
+		acc = f(acc, v)
+		next := acc
+		mu.Unlock()
+		out.set(next)
+	}))
+	return out
+}
+
+// DistinctUntilChanged follows src but ignores values equal to the current
+// one according to equal. Use it for types that are not comparable with ==.
+func DistinctUntilChanged[T any](src ReadonlyObservable[T], equal func(a, b T) bool) *Readonly[T] {
+	out := newReadonlyWithEqual(Untrack(src.Get), equal)
+	out.onDispose(changes(src, out.set))
+	return out
+}
+
+// Zip pairs the n-th change of a with the n-th change of b. It starts with
+// the current values of both; a change waits until the other side has a
+// change to pair it with.
+func Zip[A, B any](a ReadonlyObservable[A], b ReadonlyObservable[B]) *Readonly[Pair[A, B]] {
+	var (
+		mu     sync.Mutex
+		queueA []A
+		queueB []B
+	)
+	out := newReadonlyWithEqual(Pair[A, B]{Untrack(a.Get), Untrack(b.Get)}, nil)
+	// take must be called with mu held.
+	take := func() (Pair[A, B], bool) {
+		if len(queueA) == 0 || len(queueB) == 0 {
+			return Pair[A, B]{}, false
+		}
+		p := Pair[A, B]{queueA[0], queueB[0]}
+		queueA, queueB = queueA[1:], queueB[1:]
+		return p, true
+	}
+	out.onDispose(changes(a, func(v A) {
+		mu.Lock()
+		queueA = append(queueA, v)
+		p, ok := take()
+		mu.Unlock()
+		if ok {
+			out.set(p)
+		}
+	}))
+	out.onDispose(changes(b, func(v B) {
+		mu.Lock()
+		queueB = append(queueB, v)
+		p, ok := take()
+		mu.Unlock()
+		if ok {
+			out.set(p)
+		}
+	}))
+	return out
+}
+
+// Pairwise holds the previous and current value of src as First and
+// Second. Both start as the current value.
+func Pairwise[T any](src ReadonlyObservable[T]) *Readonly[Pair[T, T]] {
+	var mu sync.Mutex
+	cur := Untrack(src.Get)
+	out := newReadonlyWithEqual(Pair[T, T]{cur, cur}, nil)
+	out.onDispose(changes(src, func(v T) {
+		mu.Lock()
+		p := Pair[T, T]{cur, v}
+		cur = v
+		mu.Unlock()
+		out.set(p)
+	}))
+	return out
+}
+
+// StartWith holds first until src changes, then follows src.
+func StartWith[T any](src ReadonlyObservable[T], first T) *Readonly[T] {
+	out := newReadonly(first)
+	out.onDispose(changes(src, out.set))
+	return out
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+// record subscribes to src and returns the values it was called with.
+func record[T any](t *testing.T, src ReadonlyObservable[T]) *[]T {
+	t.Helper()
+	var seen []T
+	t.Cleanup(src.Subscribe(func(v T) { seen = append(seen, v) }))
+	return &seen
+}
+
+func TestCombine2(t *testing.T) {
+	a, b := NewObservable(1), NewObservable("x")
+	c := Combine2(a, b, func(n int, s string) string { return s + string(rune('0'+n)) })
+	seen := record(t, c)
+
+	Batch(func() {
+		a.Set(2)
+		b.Set("y")
+	})
+	if want := []string{"x1", "y2"}; !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+
+	c.Dispose()
+	a.Set(3)
+	if got := c.Get(); got != "y2" {
+		t.Fatalf("after Dispose = %q, want y2", got)
+	}
+}
+
+func TestCombine3(t *testing.T) {
+	a, b, c := NewObservable(1), NewObservable(2), NewObservable(3)
+	sum := Combine3(a, b, c, func(x, y, z int) int { return x + y + z })
+	seen := record(t, sum)
+
+	c.Set(10)
+	if want := []int{6, 13}; !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+
+	sum.Dispose()
+	a.Set(100)
+	if got := sum.Get(); got != 13 {
+		t.Fatalf("after Dispose = %d, want 13", got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	src := NewObservable(1)
+	even := Filter[int](src, func(v int) bool { return v%2 == 0 })
+	seen := record(t, even)
+
+	for _, v := range []int{2, 3, 4} {
+		src.Set(v)
+	}
+	if want := []int{0, 2, 4}; !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+
+	even.Dispose()
+	src.Set(6)
+	if got := even.Get(); got != 4 {
+		t.Fatalf("after Dispose = %d, want 4", got)
+	}
+}
+
+func TestScan(t *testing.T) {
+	src := NewObservable(1)
+	total := Scan[int](src, 0, func(acc, v int) int { return acc + v })
+	seen := record(t, total)
+
+	src.Set(2)
+	src.Set(3)
+	if want := []int{1, 3, 6}; !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+
+	total.Dispose()
+	src.Set(4)
+	if got := total.Get(); got != 6 {
+		t.Fatalf("after Dispose = %d, want 6", got)
+	}
+}
+
+func TestDistinctUntilChanged(t *testing.T) {
+	src := NewObservableWithEqual([]int{1}, nil)
+	distinct := DistinctUntilChanged[[]int](src, slices.Equal[[]int])
+	seen := record(t, distinct)
+
+	src.Set([]int{1})
+	src.Set([]int{1, 2})
+	src.Set([]int{1, 2})
+	if len(*seen) != 2 || !slices.Equal((*seen)[1], []int{1, 2}) {
+		t.Fatalf("seen = %v, want [[1] [1 2]]", *seen)
+	}
+
+	distinct.Dispose()
+	src.Set([]int{3})
+	if got := distinct.Get(); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("after Dispose = %v, want [1 2]", got)
+	}
+}
+
+func TestZip(t *testing.T) {
+	a, b := NewObservable(0), NewObservable("")
+	zipped := Zip[int, string](a, b)
+	seen := record(t, zipped)
+
+	a.Set(1)
+	a.Set(2)
+	b.Set("one")
+	b.Set("two")
+	want := []Pair[int, string]{{0, ""}, {1, "one"}, {2, "two"}}
+	if !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+
+	zipped.Dispose()
+	a.Set(3)
+	b.Set("three")
+	if got := zipped.Get(); got != want[2] {
+		t.Fatalf("after Dispose = %v, want %v", got, want[2])
+	}
+}
+
+func TestPairwise(t *testing.T) {
+	src := NewObservable(1)
+	pairs := Pairwise[int](src)
+	seen := record(t, pairs)
+
+	src.Set(2)
+	src.Set(3)
+	want := []Pair[int, int]{{1, 1}, {1, 2}, {2, 3}}
+	if !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+
+	pairs.Dispose()
+	src.Set(4)
+	if got := pairs.Get(); got != want[2] {
+		t.Fatalf("after Dispose = %v, want %v", got, want[2])
+	}
+}
+
+func TestStartWith(t *testing.T) {
+	src := NewObservable("live")
+	s := StartWith[string](src, "loading")
+	seen := record(t, s)
+
+	src.Set("next")
+	if want := []string{"loading", "next"}; !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+
+	s.Dispose()
+	src.Set("later")
+	if got := s.Get(); got != "next" {
+		t.Fatalf("after Dispose = %q, want next", got)
+	}
+}
+
+func TestOperatorsInsideEffect(t *testing.T) {
+	src := NewObservable(1)
+	trigger := NewObservable(0)
+	runs := 0
+	var last *Readonly[int]
+	dispose := Effect(func() func() {
+		trigger.Get()
+		runs++
+		last = Scan[int](src, 0, func(acc, v int) int { return acc + v })
+		return nil
+	})
+
+	src.Set(2)
+	if runs != 1 {
+		t.Fatalf("Effect re-ran %d times on a change to the operator's source", runs-1)
+	}
+	trigger.Set(1)
+	trigger.Set(2)
+	if n := len(src.listeners); n != 1 {
+		t.Fatalf("src has %d listeners after 3 runs, want 1", n)
+	}
+
+	dispose()
+	if n := len(src.listeners); n != 0 {
+		t.Fatalf("src has %d listeners after dispose, want 0", n)
+	}
+	src.Set(3)
+	if got := last.Get(); got != 2 {
+		t.Fatalf("disposed Scan = %d, want 2", got)
+	}
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// === Persistence ===
+
+// ErrQuotaExceeded is returned by Storage.SetItem when the backend is full.
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// Storage is a string key/value store such as the browser's localStorage.
+type Storage interface {
+	GetItem(key string) (string, bool)
+	SetItem(key, value string) error
+	RemoveItem(key string)
+}
+
+// DefaultStorage is used by Persist unless WithStorage is given. In the
+// browser it is localStorage; elsewhere it is an in-memory store.
+var DefaultStorage Storage = NewMemoryStorage()
+
+// MemoryStorage is an in-memory Storage, for tests and non-browser builds.
+// A positive Quota limits the total size of keys and values in bytes.
+type MemoryStorage struct {
+	Quota int
+
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{items: map[string]string{}}
+}
+
+func (m *MemoryStorage) GetItem(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.items[key]
+	return v, ok
+}
+
+func (m *MemoryStorage) SetItem(key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Quota > 0 {
+		size := len(key) + len(value)
+		for k, v := range m.items {
+			if k != key {
+				size += len(k) + len(v)
+			}
+		}
+		if size > m.Quota {
+			return ErrQuotaExceeded
+		}
+	}
+	m.items[key] = value
+	return nil
+}
+
+func (m *MemoryStorage) RemoveItem(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, key)
+}
+
+// Codec converts values to and from their stored form.
+type Codec[T any] interface {
+	Encode(v T) (string, error)
+	Decode(s string) (T, error)
+}
+
+// JSONCodec stores values as JSON. It is the default codec.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(v T) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func (JSONCodec[T]) Decode(s string) (T, error) {
+	var v T
+	err := json.Unmarshal([]byte(s), &v)
+	return v, err
+}
+
+// Migration upgrades data stored under schema version from to the next
+// version, from+1.
+type Migration func(from int, data string) (string, error)
+
+// PersistOption configures Persist.
+type PersistOption func(*persistConfig)
+
+type persistConfig struct {
+	storage Storage
+	version int
+	migrate Migration
+	onError func(error)
+}
+
+// WithStorage makes Persist use s instead of DefaultStorage.
+func WithStorage(s Storage) PersistOption {
+	return func(cfg *persistConfig) { cfg.storage = s }
+}
+
+// WithSchema tags stored data with version. Data stored under an older
+// version is passed through migrate one version at a time before decoding.
+func WithSchema(version int, migrate Migration) PersistOption {
+	return func(cfg *persistConfig) {
+		cfg.version = version
+		cfg.migrate = migrate
+	}
+}
+
+// OnPersistError receives errors from loading or saving, such as
+// ErrQuotaExceeded. By default they are logged.
+func OnPersistError(fn func(error)) PersistOption {
+	return func(cfg *persistConfig) { cfg.onError = fn }
+}
+
+// persistEnvelope is the stored form: the encoded value and its schema
+// version. Values stored without an envelope are treated as version 0.
+type persistEnvelope struct {
+	Version int    `json:"v"`
+	Data    string `json:"data"`
+}
+
+// Persist hydrates obs from storage under key, then writes it back on every
+// change. A nil codec means JSONCodec. If nothing is stored, or the stored
+// data cannot be loaded, obs keeps its value. The returned func stops
+// writing.
+func Persist[T any](obs *Observable[T], key string, codec Codec[T], opts ...PersistOption) func() {
+	cfg := persistConfig{
+		storage: DefaultStorage,
+		onError: func(err error) { log.Println("persist:", err) },
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if codec == nil {
+		codec = JSONCodec[T]{}
+	}
+
+	if raw, ok := cfg.storage.GetItem(key); ok {
+		if v, err := loadPersisted(raw, codec, cfg); err != nil {
+			cfg.onError(fmt.Errorf("loading %q: %w", key, err))
+		} else {
+			obs.Set(v)
+		}
+	}
+
+	return changes[T](obs, func(v T) {
+		data, err := codec.Encode(v)
+		if err == nil {
+			var b []byte
+			b, err = json.Marshal(persistEnvelope{Version: cfg.version, Data: data})
+			if err == nil {
+				err = cfg.storage.SetItem(key, string(b))
+			}
+		}
+		if err != nil {
+			cfg.onError(fmt.Errorf("saving %q: %w", key, err))
+		}
+	})
+}
+
+func loadPersisted[T any](raw string, codec Codec[T], cfg persistConfig) (T, error) {
+	var zero T
+	env := persistEnvelope{Data: raw}
+	var stored struct {
+		Version int     `json:"v"`
+		Data    *string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(raw), &stored); err == nil && stored.Data != nil {
+		env = persistEnvelope{Version: stored.Version, Data: *stored.Data}
+	}
+	if env.Version > cfg.version {
+		return zero, fmt.Errorf("stored schema version %d is newer than %d", env.Version, cfg.version)
+	}
+	for env.Version < cfg.version {
+		if cfg.migrate == nil {
+			return zero, fmt.Errorf("no migration from schema version %d", env.Version)
+		}
+		data, err := cfg.migrate(env.Version, env.Data)
+		if err != nil {
+			return zero, fmt.Errorf("migrating from schema version %d: %w", env.Version, err)
+		}
+		env.Version++
+		env.Data = data
+	}
+	return codec.Decode(env.Data)
+}
+
+// NewPersistentObservable creates an observable hydrated from storage under
+// key, falling back to initial, and persisted on every change.
+func NewPersistentObservable[T any](key string, initial T, codec Codec[T], opts ...PersistOption) *Observable[T] {
+	obs := NewObservable(initial)
+	Persist(obs, key, codec, opts...)
+	return obs
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestPersistRoundTrip(t *testing.T) {
+	store := NewMemoryStorage()
+	obs := NewPersistentObservable("count", 1, nil, WithStorage(store))
+	obs.Set(7)
+
+	again := NewPersistentObservable("count", 0, nil, WithStorage(store))
+	if got := again.Get(); got != 7 {
+		t.Fatalf("hydrated = %d, want 7", got)
+	}
+}
+
+func TestPersistMigrates(t *testing.T) {
+	store := NewMemoryStorage()
+	store.SetItem("n", "3")
+
+	obs := NewPersistentObservable("n", 0, nil, WithStorage(store),
+		WithSchema(1, func(from int, data string) (string, error) {
+			n, err := strconv.Atoi(data)
+			return strconv.Itoa(n * 10), err
+		}))
+	if got := obs.Get(); got != 30 {
+		t.Fatalf("migrated = %d, want 30", got)
+	}
+}
+
+func TestMemoryStorageQuota(t *testing.T) {
+	store := NewMemoryStorage()
+	store.Quota = 10
+	if err := store.SetItem("k", "1234"); err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []error
+	obs := NewPersistentObservable("big", "", nil, WithStorage(store),
+		OnPersistError(func(err error) { errs = append(errs, err) }))
+	obs.Set("far too long")
+	if len(errs) != 1 || !errors.Is(errs[0], ErrQuotaExceeded) {
+		t.Fatalf("errors = %v, want ErrQuotaExceeded", errs)
+	}
+
+	store.RemoveItem("k")
+	if _, ok := store.GetItem("k"); ok {
+		t.Fatal("item still present after RemoveItem")
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import "sync"
+
+// === Readonly ===
+
+// Readonly is a read-only observable whose value is driven by an operator
+// such as Debounce. It can be read from Derive and Effect like any other
+// source. Dispose detaches it from its upstream sources. Like a Derived, a
+// Readonly created inside a computation is disposed when that computation
+// re-runs or is disposed.
+type Readonly[T any] struct {
+	obs *Observable[T]
+
+	mu   sync.Mutex
+	stop []func()
+}
+
+func newReadonly[T any](initial T) *Readonly[T] {
+	return newReadonlyWithEqual(initial, defaultEqual[T]())
+}
+
+func newReadonlyWithEqual[T any](initial T, equal func(a, b T) bool) *Readonly[T] {
+	r := &Readonly[T]{obs: NewObservableWithEqual(initial, equal)}
+	adopt(r.Dispose)
+	return r
+}
+
+func (r *Readonly[T]) Get() T { return r.obs.Get() }
+
+func (r *Readonly[T]) Subscribe(fn func(T)) func() { return r.obs.Subscribe(fn) }
+
+// Dispose stops the operator; the last value is kept.
+func (r *Readonly[T]) Dispose() {
+	r.mu.Lock()
+	stop := r.stop
+	r.stop = nil
+	r.mu.Unlock()
+	for _, s := range stop {
+		s()
+	}
+}
+
+// Named gives r a debug name and registers it for DumpGraph.
+func (r *Readonly[T]) Named(name string) *Readonly[T] {
+	r.obs.Named(name)
+	return r
+}
+
+// onDispose registers stop to run when r is disposed.
+func (r *Readonly[T]) onDispose(stop func()) {
+	r.mu.Lock()
+	r.stop = append(r.stop, stop)
+	r.mu.Unlock()
+}
+
+func (r *Readonly[T]) set(v T) { r.obs.Set(v) }
+
+// changes subscribes fn to src, skipping the immediate call Subscribe makes
+// with the current value.
+func changes[T any](src ReadonlyObservable[T], fn func(T)) func() {
+	first := true
+	return src.Subscribe(func(v T) {
+		if first {
+			first = false
+			return
+		}
+		fn(v)
+	})
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// === Resource ===
+
+// Resource holds the result of an asynchronous fetch and exposes its
+// loading, error and data states as observables.
+type Resource[T any] struct {
+	loading *Observable[bool]
+	err     *Observable[error]
+	data    *Observable[T]
+
+	mu      sync.Mutex
+	seq     uint64
+	cancel  context.CancelFunc
+	refetch func()
+	stop    func()
+}
+
+// NewResource calls fetch with the value returned by source, and again
+// whenever an observable read by source changes. source is tracked like a
+// Derive; fetch runs on its own goroutine. Starting a new fetch cancels the
+// context of the one still in flight, and its result is discarded. On error
+// Data keeps its previous value. A panic in fetch is reported like one in a
+// computation created where NewResource was called, and becomes the error.
+func NewResource[S, T any](source func() S, fetch func(ctx context.Context, src S) (T, error)) *Resource[T] {
+	var zero T
+	r := &Resource[T]{
+		loading: NewObservable(false),
+		err:     NewObservable[error](nil),
+		data:    NewObservableWithEqual(zero, nil),
+	}
+
+	scope := currentScope()
+	var (
+		lastMu sync.Mutex
+		last   S
+	)
+	start := func(src S) {
+		lastMu.Lock()
+		last = src
+		lastMu.Unlock()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		r.mu.Lock()
+		if r.cancel != nil {
+			r.cancel()
+		}
+		r.cancel = cancel
+		r.seq++
+		seq := r.seq
+		r.mu.Unlock()
+
+		r.loading.Set(true)
+		go func() {
+			defer cancel()
+			v, err := callFetch(scope, fetch, ctx, src)
+
+			Batch(func() {
+				// Check only now: while this waited for the batch, a flush
+				// may have re-run the source and started a newer fetch.
+				r.mu.Lock()
+				current := seq == r.seq && ctx.Err() == nil
+				if current {
+					r.cancel = nil
+				}
+				r.mu.Unlock()
+				if !current {
+					return
+				}
+
+				if err != nil {
+					r.err.Set(err)
+				} else {
+					r.data.Set(v)
+					r.err.Set(nil)
+				}
+				r.loading.Set(false)
+			})
+		}()
+	}
+
+	r.refetch = func() {
+		lastMu.Lock()
+		src := last
+		lastMu.Unlock()
+		start(src)
+	}
+	r.stop = Effect(func() func() {
+		start(source())
+		return nil
+	})
+	return r
+}
+
+// callFetch calls fetch, reporting a panic to s and returning it as the
+// error.
+func callFetch[S, T any](s *scope, fetch func(context.Context, S) (T, error), ctx context.Context, src S) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			perr := newPanicError("resource fetch", r)
+			reportError(s, perr)
+			err = perr
+		}
+	}()
+	return fetch(ctx, src)
+}
+
+// Loading reports whether a fetch is in flight.
+func (r *Resource[T]) Loading() ReadonlyObservable[bool] { return r.loading }
+
+// Error holds the error returned by the latest fetch, or nil.
+func (r *Resource[T]) Error() ReadonlyObservable[error] { return r.err }
+
+// Data holds the value returned by the latest successful fetch.
+func (r *Resource[T]) Data() ReadonlyObservable[T] { return r.data }
+
+// Refetch fetches again with the current source value.
+func (r *Resource[T]) Refetch() {
+	r.refetch()
+}
+
+// Dispose stops tracking the source and cancels any fetch in flight.
+func (r *Resource[T]) Dispose() {
+	r.stop()
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+	r.seq++
+	r.mu.Unlock()
+	r.loading.Set(false)
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitLoaded waits for r to finish its fetch.
+func waitLoaded[T any](t *testing.T, r *Resource[T]) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for r.Loading().Get() {
+		if time.Now().After(deadline) {
+			t.Fatal("fetch did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestResourceFetches(t *testing.T) {
+	id := NewObservable(1)
+	r := NewResource(id.Get, func(_ context.Context, id int) (int, error) {
+		return id * 10, nil
+	})
+	defer r.Dispose()
+
+	waitLoaded(t, r)
+	if got := r.Data().Get(); got != 10 {
+		t.Fatalf("Data = %d, want 10", got)
+	}
+	id.Set(2)
+	waitLoaded(t, r)
+	if got := r.Data().Get(); got != 20 {
+		t.Fatalf("Data = %d, want 20", got)
+	}
+}
+
+func TestResourceFetchPanic(t *testing.T) {
+	reported := make(chan error, 1)
+	var r *Resource[int]
+	dispose := Catch(func(err error) { reported <- err }, func() {
+		r = NewResource(func() int { return 1 }, func(context.Context, int) (int, error) {
+			panic("boom")
+		})
+	})
+	defer dispose()
+	defer r.Dispose()
+
+	var perr *PanicError
+	select {
+	case err := <-reported:
+		if !errors.As(err, &perr) || perr.Value != "boom" {
+			t.Fatalf("reported %v, want the fetch panic", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("panic was not reported")
+	}
+	waitLoaded(t, r)
+	if err := r.Error().Get(); !errors.As(err, &perr) {
+		t.Fatalf("Error = %v, want a *PanicError", err)
+	}
+}
+
+func TestResourceDiscardsResultSupersededWhileWaiting(t *testing.T) {
+	id := NewObservable(1)
+	release := map[int]chan struct{}{1: make(chan struct{}), 2: make(chan struct{})}
+	r := NewResource(id.Get, func(_ context.Context, id int) (int, error) {
+		<-release[id]
+		return id * 10, nil
+	})
+	defer r.Dispose()
+	defer close(release[2])
+
+	Batch(func() {
+		close(release[1])
+		// Let the first fetch return and wait for this batch, then
+		// supersede it from the flush that ends the batch.
+		time.Sleep(20 * time.Millisecond)
+		id.Set(2)
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	if !r.Loading().Get() {
+		t.Fatal("Loading = false while the second fetch is in flight")
+	}
+	if got := r.Data().Get(); got != 0 {
+		t.Fatalf("Data = %d from a superseded fetch, want 0", got)
+	}
+}
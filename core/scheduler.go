@@ -0,0 +1,147 @@
+package core
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// === Scheduling ===
+
+// Scheduler decides when DOM-bound work runs: the re-runs of a RenderEffect
+// and the updates of a Bind. Tasks are identified by key; scheduling a key
+// that is already pending replaces its task, so a burst of changes results
+// in a single update with the latest state.
+type Scheduler interface {
+	Schedule(key any, task func())
+}
+
+// SyncScheduler runs every task immediately. It is the default.
+var SyncScheduler Scheduler = syncScheduler{}
+
+type syncScheduler struct{}
+
+func (syncScheduler) Schedule(_ any, task func()) { task() }
+
+var scheduler atomic.Pointer[Scheduler]
+
+// SetScheduler sets the scheduler used by RenderEffect and Bind. A nil s
+// restores SyncScheduler.
+func SetScheduler(s Scheduler) {
+	if s == nil {
+		scheduler.Store(nil)
+		return
+	}
+	scheduler.Store(&s)
+}
+
+func currentScheduler() Scheduler {
+	if s := scheduler.Load(); s != nil {
+		return *s
+	}
+	return SyncScheduler
+}
+
+// taskQueue holds pending tasks in the order their keys were first
+// scheduled, one per key.
+type taskQueue struct {
+	mu    sync.Mutex
+	keys  map[any]int
+	tasks []func()
+}
+
+// add schedules task under key and reports whether the queue was empty,
+// i.e. whether a run must be requested.
+func (q *taskQueue) add(key any, task func()) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if i, ok := q.keys[key]; ok {
+		q.tasks[i] = task
+		return false
+	}
+	if q.keys == nil {
+		q.keys = map[any]int{}
+	}
+	q.keys[key] = len(q.tasks)
+	q.tasks = append(q.tasks, task)
+	return len(q.tasks) == 1
+}
+
+// run runs the tasks pending when it is called. Tasks they schedule wait
+// for the next run.
+func (q *taskQueue) run() {
+	q.mu.Lock()
+	tasks := q.tasks
+	q.tasks = nil
+	clear(q.keys)
+	q.mu.Unlock()
+	for _, task := range tasks {
+		task()
+	}
+}
+
+func (q *taskQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.tasks)
+}
+
+// ManualScheduler holds tasks until Flush is called, for tests.
+type ManualScheduler struct {
+	q taskQueue
+}
+
+func NewManualScheduler() *ManualScheduler {
+	return &ManualScheduler{}
+}
+
+func (s *ManualScheduler) Schedule(key any, task func()) {
+	s.q.add(key, task)
+}
+
+// Flush runs the pending tasks, and then those they scheduled, until none
+// are left.
+func (s *ManualScheduler) Flush() {
+	for i := 0; i < maxFlushes && s.q.len() > 0; i++ {
+		s.q.run()
+	}
+}
+
+// Pending returns the number of tasks waiting for Flush.
+func (s *ManualScheduler) Pending() int {
+	return s.q.len()
+}
+
+// RenderEffect is like Effect, but its re-runs are handed to the current
+// Scheduler instead of happening during propagation. Its first run is
+// immediate.
+func RenderEffect(fn func() (cleanup func())) func() {
+	return newEffect(fn, true)
+}
+
+// Bind calls fn with the current value of src now, and with the latest one
+// through the current Scheduler after it changes. The returned func stops
+// it, including an update already scheduled.
+func Bind[T any](src ReadonlyObservable[T], fn func(T)) func() {
+	var stopped atomic.Bool
+	key := new(byte)
+	scope := currentScope()
+	first := true
+	stop := src.Subscribe(func(v T) {
+		if first {
+			first = false
+			fn(v)
+			return
+		}
+		currentScheduler().Schedule(key, func() {
+			if stopped.Load() {
+				return
+			}
+			defer recoverTo(scope, "binding")
+			fn(v)
+		})
+	})
+	return func() {
+		stopped.Store(true)
+		stop()
+	}
+}
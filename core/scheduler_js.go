@@ -0,0 +1,37 @@
+//go:build js && wasm
+
+package core
+
+import "syscall/js"
+
+// jsScheduler runs its queue from a browser callback requested when the
+// first task is scheduled.
+type jsScheduler struct {
+	q       taskQueue
+	request string
+}
+
+// MicrotaskScheduler runs tasks in a microtask, after the current event
+// handler returns but before the browser renders.
+func MicrotaskScheduler() Scheduler {
+	return &jsScheduler{request: "queueMicrotask"}
+}
+
+// AnimationFrameScheduler runs tasks just before the next repaint, so a
+// burst of changes touches the DOM once per frame.
+func AnimationFrameScheduler() Scheduler {
+	return &jsScheduler{request: "requestAnimationFrame"}
+}
+
+func (s *jsScheduler) Schedule(key any, task func()) {
+	if !s.q.add(key, task) {
+		return
+	}
+	var cb js.Func
+	cb = js.FuncOf(func(js.Value, []js.Value) any {
+		cb.Release()
+		s.q.run()
+		return nil
+	})
+	js.Global().Call(s.request, cb)
+}
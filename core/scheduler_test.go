@@ -0,0 +1,66 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+// useScheduler makes s the current scheduler for the rest of the test.
+func useScheduler(t *testing.T, s Scheduler) {
+	SetScheduler(s)
+	t.Cleanup(func() { SetScheduler(nil) })
+}
+
+func TestBindCoalescesThroughScheduler(t *testing.T) {
+	sched := NewManualScheduler()
+	useScheduler(t, sched)
+
+	src := NewObservable(1)
+	var seen []int
+	stop := Bind[int](src, func(v int) { seen = append(seen, v) })
+	defer stop()
+
+	src.Set(2)
+	src.Set(3)
+	if !slices.Equal(seen, []int{1}) || sched.Pending() != 1 {
+		t.Fatalf("before Flush: seen = %v, pending = %d", seen, sched.Pending())
+	}
+	sched.Flush()
+	if want := []int{1, 3}; !slices.Equal(seen, want) {
+		t.Fatalf("seen = %v, want %v", seen, want)
+	}
+
+	src.Set(4)
+	stop()
+	sched.Flush()
+	if want := []int{1, 3}; !slices.Equal(seen, want) {
+		t.Fatalf("ran after stop: seen = %v", seen)
+	}
+}
+
+func TestRenderEffectRunsOnFlush(t *testing.T) {
+	sched := NewManualScheduler()
+	useScheduler(t, sched)
+
+	a := NewObservable(1)
+	double := Derive(func() int { return a.Get() * 2 })
+	var seen []int
+	dispose := RenderEffect(func() func() {
+		seen = append(seen, double.Get())
+		return nil
+	})
+	defer dispose()
+
+	a.Set(2)
+	a.Set(3)
+	if !slices.Equal(seen, []int{2}) {
+		t.Fatalf("ran before Flush: %v", seen)
+	}
+	sched.Flush()
+	if want := []int{2, 6}; !slices.Equal(seen, want) {
+		t.Fatalf("seen = %v, want %v", seen, want)
+	}
+	if sched.Pending() != 0 {
+		t.Fatalf("pending = %d after Flush", sched.Pending())
+	}
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// === Tracking scopes ===
+//
+// Every Derived and Effect owns a scope, and each of its runs happens inside
+// that scope. Scopes form a tree that mirrors how computations were created:
+// a computation created while another one runs is owned by it, and is
+// disposed when its owner re-runs or is disposed. The active scope is kept
+// per goroutine, so reads made on other goroutines while a computation runs
+// are never attributed to it, and concurrent runs cannot see each other.
+//
+// This assumes a computation runs entirely on the goroutine that started
+// it: observables read from a goroutine that compute spawns are not
+// tracked. Go has no supported goroutine identity, so goid parses it from
+// runtime.Stack, which costs about 5µs. Gets look it up only while some
+// scope is active (see activeScopes), so plain Gets outside of any
+// computation never do. startBatch, however, needs it on every change: once
+// to take ownership of propagation, and again for each change made while
+// propagation is already owned, such as every Set inside a Batch, a
+// subscriber or an Effect, to tell the owner apart from other goroutines.
+
+type scope struct {
+	ctx     context.Context
+	parent  *scope
+	tracker *dependencyTracker
+	// borrowed scopes hand ownership of new computations to their parent.
+	borrowed bool
+	// onError, if set, receives errors reported from within s; see Catch.
+	onError func(error)
+
+	mu    sync.Mutex
+	owned []func()
+}
+
+type dependencyTracker struct {
+	add func(internalObservable)
+}
+
+var (
+	scopesMu     sync.Mutex
+	scopes       = map[uint64]*scope{}
+	activeScopes atomic.Int32
+)
+
+func newScope(ctx context.Context) *scope {
+	return &scope{ctx: ctx, parent: currentScope()}
+}
+
+// own registers dispose to run when s re-runs or is disposed.
+func (s *scope) own(dispose func()) {
+	for s.borrowed && s.parent != nil {
+		s = s.parent
+	}
+	s.mu.Lock()
+	s.owned = append(s.owned, dispose)
+	s.mu.Unlock()
+}
+
+// disposeOwned disposes everything created during the last run, newest first.
+func (s *scope) disposeOwned() {
+	s.mu.Lock()
+	owned := s.owned
+	s.owned = nil
+	s.mu.Unlock()
+	for i := len(owned) - 1; i >= 0; i-- {
+		owned[i]()
+	}
+}
+
+// adopt registers dispose with the scope active on this goroutine, if any.
+func adopt(dispose func()) {
+	if s := currentScope(); s != nil {
+		s.own(dispose)
+	}
+}
+
+// withScope runs fn with s as the active scope of the calling goroutine.
+// A nil s runs fn outside of any computation.
+func withScope(s *scope, fn func()) {
+	id := goid()
+
+	scopesMu.Lock()
+	prev, hadPrev := scopes[id]
+	setScope(id, s)
+	scopesMu.Unlock()
+
+	defer func() {
+		scopesMu.Lock()
+		if hadPrev {
+			setScope(id, prev)
+		} else {
+			setScope(id, nil)
+		}
+		scopesMu.Unlock()
+	}()
+
+	fn()
+}
+
+// setScope must be called with scopesMu held.
+func setScope(id uint64, s *scope) {
+	if s == nil {
+		delete(scopes, id)
+	} else {
+		scopes[id] = s
+	}
+	activeScopes.Store(int32(len(scopes)))
+}
+
+func currentScope() *scope {
+	if activeScopes.Load() == 0 {
+		return nil
+	}
+	id := goid()
+	scopesMu.Lock()
+	defer scopesMu.Unlock()
+	return scopes[id]
+}
+
+// goid returns the id of the calling goroutine, as printed in the first
+// line of its stack trace. It is slow; see the note at the top of the file.
+func goid() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	b := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// Untrack runs fn without recording the observables it reads as
+// dependencies of the surrounding Derive or Effect. Computations created
+// inside fn are still owned by the surrounding one.
+func Untrack[T any](fn func() T) T {
+	var out T
+	cur := currentScope()
+	if cur == nil {
+		return fn()
+	}
+	withScope(&scope{ctx: cur.ctx, parent: cur, borrowed: true}, func() {
+		out = fn()
+	})
+	return out
+}
+
+// ==== Observable hook ====
+
+// tracking reports whether reads on this goroutine are being recorded.
+func tracking() bool {
+	s := currentScope()
+	return s != nil && s.tracker != nil
+}
+
+func trackObservable(obs internalObservable) {
+	if s := currentScope(); s != nil && s.tracker != nil {
+		s.tracker.add(obs)
+	}
+}
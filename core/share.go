@@ -0,0 +1,170 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// === Cross-tab sharing ===
+
+// Transport delivers messages to every other peer listening on the same
+// channel, such as the other tabs of an app. A peer does not receive its own
+// messages.
+type Transport interface {
+	Post(msg string) error
+	Listen(fn func(msg string)) (stop func())
+}
+
+// shareMessage is the wire format used by Share.
+type shareMessage struct {
+	From string `json:"from"`
+	// Kind is "set" for a new value and "sync" for a request to resend it.
+	Kind string `json:"kind"`
+	Data string `json:"data,omitempty"`
+}
+
+// Share keeps obs in step with the observables shared under the same
+// transport in other tabs. Local changes are encoded with codec (JSONCodec
+// if nil) and posted; changes received from peers are applied without being
+// posted back, so two tabs never echo a value between them. On start, Share
+// asks its peers for their current value. The returned func stops sharing.
+func Share[T any](obs *Observable[T], transport Transport, codec Codec[T]) func() {
+	if codec == nil {
+		codec = JSONCodec[T]{}
+	}
+	self := newPeerID()
+
+	var (
+		mu         sync.Mutex
+		applying   bool
+		appliedVer uint64
+	)
+
+	post := func(kind string, v T) {
+		msg := shareMessage{From: self, Kind: kind}
+		if kind == "set" {
+			data, err := codec.Encode(v)
+			if err != nil {
+				log.Println("share:", err)
+				return
+			}
+			msg.Data = data
+		}
+		b, err := json.Marshal(msg)
+		if err == nil {
+			err = transport.Post(string(b))
+		}
+		if err != nil {
+			log.Println("share:", err)
+		}
+	}
+
+	stopListen := transport.Listen(func(raw string) {
+		var msg shareMessage
+		if err := json.Unmarshal([]byte(raw), &msg); err != nil || msg.From == self {
+			return
+		}
+		switch msg.Kind {
+		case "sync":
+			post("set", Untrack(obs.Get))
+		case "set":
+			v, err := codec.Decode(msg.Data)
+			if err != nil {
+				log.Println("share:", fmt.Errorf("decoding from %s: %w", msg.From, err))
+				return
+			}
+			mu.Lock()
+			applying = true
+			mu.Unlock()
+			obs.Set(v)
+			mu.Lock()
+			applying = false
+			appliedVer = obs.version()
+			mu.Unlock()
+		}
+	})
+
+	stopChanges := changes[T](obs, func(v T) {
+		ver := obs.version()
+		mu.Lock()
+		remote := applying || ver == appliedVer
+		mu.Unlock()
+		if !remote {
+			post("set", v)
+		}
+	})
+
+	var zero T
+	post("sync", zero)
+
+	return func() {
+		stopChanges()
+		stopListen()
+	}
+}
+
+func newPeerID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// MemoryBus is an in-process Transport hub for tests: every Transport opened
+// on the same bus and name receives the messages posted by the others.
+type MemoryBus struct {
+	mu        sync.Mutex
+	listeners map[string][]*memoryListener
+}
+
+type memoryListener struct {
+	owner *memoryTransport
+	fn    func(string)
+}
+
+type memoryTransport struct {
+	bus  *MemoryBus
+	name string
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{listeners: map[string][]*memoryListener{}}
+}
+
+// Open returns a new peer on the channel called name.
+func (b *MemoryBus) Open(name string) Transport {
+	return &memoryTransport{bus: b, name: name}
+}
+
+func (t *memoryTransport) Post(msg string) error {
+	t.bus.mu.Lock()
+	listeners := t.bus.listeners[t.name]
+	t.bus.mu.Unlock()
+	for _, l := range listeners {
+		if l.owner != t {
+			l.fn(msg)
+		}
+	}
+	return nil
+}
+
+func (t *memoryTransport) Listen(fn func(string)) func() {
+	l := &memoryListener{owner: t, fn: fn}
+	t.bus.mu.Lock()
+	t.bus.listeners[t.name] = append(t.bus.listeners[t.name], l)
+	t.bus.mu.Unlock()
+	return func() {
+		t.bus.mu.Lock()
+		defer t.bus.mu.Unlock()
+		ls := t.bus.listeners[t.name]
+		for i, other := range ls {
+			if other == l {
+				t.bus.listeners[t.name] = append(ls[:i:i], ls[i+1:]...)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+//go:build js && wasm
+
+package core
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall/js"
+)
+
+// BroadcastTransport returns a Transport over the BroadcastChannel called
+// name. Where BroadcastChannel is unavailable it falls back to storage
+// events on localStorage, which reach other tabs of the same origin.
+func BroadcastTransport(name string) Transport {
+	if ctor := js.Global().Get("BroadcastChannel"); ctor.Type() == js.TypeFunction {
+		return broadcastTransport{ctor.New(name)}
+	}
+	return storageTransport{key: "swiftlygo:channel:" + name}
+}
+
+type broadcastTransport struct {
+	ch js.Value
+}
+
+func (t broadcastTransport) Post(msg string) error {
+	t.ch.Call("postMessage", msg)
+	return nil
+}
+
+func (t broadcastTransport) Listen(fn func(string)) func() {
+	handler := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if data := args[0].Get("data"); data.Type() == js.TypeString {
+			fn(data.String())
+		}
+		return nil
+	})
+	t.ch.Call("addEventListener", "message", handler)
+	return func() {
+		t.ch.Call("removeEventListener", "message", handler)
+		handler.Release()
+	}
+}
+
+// storageTransport posts by writing to localStorage. Each write carries a
+// sequence prefix so that posting the same message twice still fires a
+// storage event in the other tabs.
+type storageTransport struct {
+	key string
+}
+
+var storageSeq atomic.Uint64
+
+func (t storageTransport) Post(msg string) error {
+	seq := strconv.FormatUint(storageSeq.Add(1), 36)
+	return webStorage{js.Global().Get("localStorage")}.SetItem(t.key, seq+"|"+msg)
+}
+
+func (t storageTransport) Listen(fn func(string)) func() {
+	handler := js.FuncOf(func(this js.Value, args []js.Value) any {
+		e := args[0]
+		if e.Get("key").Type() != js.TypeString || e.Get("key").String() != t.key {
+			return nil
+		}
+		if v := e.Get("newValue"); v.Type() == js.TypeString {
+			if _, msg, ok := strings.Cut(v.String(), "|"); ok {
+				fn(msg)
+			}
+		}
+		return nil
+	})
+	js.Global().Call("addEventListener", "storage", handler)
+	return func() {
+		js.Global().Call("removeEventListener", "storage", handler)
+		handler.Release()
+	}
+}
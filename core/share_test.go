@@ -0,0 +1,48 @@
+package core
+
+import "testing"
+
+func TestMemoryBusSkipsSender(t *testing.T) {
+	bus := NewMemoryBus()
+	a, b, other := bus.Open("ch"), bus.Open("ch"), bus.Open("other")
+
+	var gotA, gotB, gotOther []string
+	a.Listen(func(m string) { gotA = append(gotA, m) })
+	stopB := b.Listen(func(m string) { gotB = append(gotB, m) })
+	other.Listen(func(m string) { gotOther = append(gotOther, m) })
+
+	a.Post("hello")
+	stopB()
+	a.Post("again")
+
+	if len(gotA) != 0 || len(gotOther) != 0 {
+		t.Fatalf("sender or other channel received: %v, %v", gotA, gotOther)
+	}
+	if len(gotB) != 1 || gotB[0] != "hello" {
+		t.Fatalf("peer received %v, want [hello]", gotB)
+	}
+}
+
+func TestShare(t *testing.T) {
+	bus := NewMemoryBus()
+	first := NewObservable(1)
+	stopFirst := Share(first, bus.Open("count"), nil)
+	defer stopFirst()
+
+	second := NewObservable(0)
+	stopSecond := Share(second, bus.Open("count"), nil)
+	if got := second.Get(); got != 1 {
+		t.Fatalf("joining peer = %d, want 1 from sync", got)
+	}
+
+	second.Set(5)
+	if got := first.Get(); got != 5 {
+		t.Fatalf("first = %d, want 5", got)
+	}
+
+	stopSecond()
+	second.Set(6)
+	if got := first.Get(); got != 5 {
+		t.Fatalf("first = %d after peer stopped, want 5", got)
+	}
+}
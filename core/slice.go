@@ -0,0 +1,261 @@
+package core
+
+import (
+	"slices"
+	"sync"
+)
+
+// === ObservableSlice ===
+
+// SliceOp identifies the kind of edit described by a SliceChange.
+type SliceOp int
+
+const (
+	// SliceInsert: Value was inserted at Index.
+	SliceInsert SliceOp = iota
+	// SliceRemove: Value was removed from Index.
+	SliceRemove
+	// SliceMove: Value moved from From to Index.
+	SliceMove
+	// SliceUpdate: the element at Index was replaced; Old holds the previous value.
+	SliceUpdate
+	// SliceReset: the whole contents were replaced.
+	SliceReset
+)
+
+// SliceChange describes a single edit to an ObservableSlice. Indices refer
+// to the slice as it was right after the edit was applied.
+type SliceChange[T any] struct {
+	Op    SliceOp
+	Index int
+	From  int
+	Value T
+	Old   T
+}
+
+// ObservableSlice is a reactive list whose edits are reported as structured
+// changes. Reading it from a Derive tracks it like an Observable; Observe
+// receives the individual edits so views can patch instead of re-render.
+type ObservableSlice[T any] struct {
+	mu        sync.Mutex
+	items     []T
+	pending   []SliceChange[T]
+	listeners []listener[[]T]
+	observers observerSet
+	watchers  []listener[[]SliceChange[T]]
+	nextID    int
+	id        uint64
+	ver       uint64
+	updated   lastUpdate
+}
+
+func NewObservableSlice[T any](items ...T) *ObservableSlice[T] {
+	return &ObservableSlice[T]{items: slices.Clone(items), id: nextNodeID()}
+}
+
+// Get returns a copy of the current items.
+func (s *ObservableSlice[T]) Get() []T {
+	trackObservable(s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.items)
+}
+
+func (s *ObservableSlice[T]) Len() int {
+	trackObservable(s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+func (s *ObservableSlice[T]) At(i int) T {
+	trackObservable(s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.items[i]
+}
+
+// Set replaces the whole contents and reports a SliceReset.
+func (s *ObservableSlice[T]) Set(items []T) {
+	s.edit(func() SliceChange[T] {
+		s.items = slices.Clone(items)
+		return SliceChange[T]{Op: SliceReset}
+	})
+}
+
+func (s *ObservableSlice[T]) Append(v T) {
+	s.edit(func() SliceChange[T] {
+		s.items = append(s.items, v)
+		return SliceChange[T]{Op: SliceInsert, Index: len(s.items) - 1, Value: v}
+	})
+}
+
+func (s *ObservableSlice[T]) Insert(i int, v T) {
+	s.edit(func() SliceChange[T] {
+		s.items = slices.Insert(s.items, i, v)
+		return SliceChange[T]{Op: SliceInsert, Index: i, Value: v}
+	})
+}
+
+func (s *ObservableSlice[T]) RemoveAt(i int) {
+	s.edit(func() SliceChange[T] {
+		v := s.items[i]
+		s.items = slices.Delete(s.items, i, i+1)
+		return SliceChange[T]{Op: SliceRemove, Index: i, Value: v}
+	})
+}
+
+// Move relocates the element at from so that it ends up at index to.
+func (s *ObservableSlice[T]) Move(from, to int) {
+	s.edit(func() SliceChange[T] {
+		v := s.items[from]
+		_ = s.items[to] // check to before changing anything
+		s.items = slices.Delete(s.items, from, from+1)
+		s.items = slices.Insert(s.items, to, v)
+		return SliceChange[T]{Op: SliceMove, Index: to, From: from, Value: v}
+	})
+}
+
+func (s *ObservableSlice[T]) Update(i int, v T) {
+	s.edit(func() SliceChange[T] {
+		old := s.items[i]
+		s.items[i] = v
+		return SliceChange[T]{Op: SliceUpdate, Index: i, Value: v, Old: old}
+	})
+}
+
+// edit applies fn under the lock and propagates the change it reports. If
+// fn panics, for instance on an index out of range, the slice is left
+// unchanged and unlocked.
+func (s *ObservableSlice[T]) edit(fn func() SliceChange[T]) {
+	startBatch()
+	defer endBatch()
+
+	func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		change := fn()
+		s.pending = append(s.pending, change)
+		s.ver++
+	}()
+	s.updated.touch()
+
+	s.observers.markStale()
+	enqueue(s)
+}
+
+// Subscribe calls fn with the current items and after every edit.
+func (s *ObservableSlice[T]) Subscribe(fn func([]T)) func() {
+	l := newListener(0, fn)
+	s.mu.Lock()
+	s.nextID++
+	l.id = s.nextID
+	s.listeners = append(s.listeners, l)
+	items := slices.Clone(s.items)
+	s.mu.Unlock()
+	l.call(s, items)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.listeners = removeByID(s.listeners, l.id)
+	}
+}
+
+// Observe calls fn with the edits made since the last propagation, in the
+// order they were applied. Edits made inside a Batch arrive together.
+func (s *ObservableSlice[T]) Observe(fn func([]SliceChange[T])) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := s.nextID
+	s.watchers = append(s.watchers, newListener(id, fn))
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.watchers = removeByID(s.watchers, id)
+	}
+}
+
+func (s *ObservableSlice[T]) addObserver(obs observer) func() { return s.observers.add(obs) }
+
+func (s *ObservableSlice[T]) height() int    { return 0 }
+func (s *ObservableSlice[T]) nodeID() uint64 { return s.id }
+
+func (s *ObservableSlice[T]) version() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ver
+}
+
+func (s *ObservableSlice[T]) flush() {
+	s.mu.Lock()
+	changes := s.pending
+	s.pending = nil
+	items := slices.Clone(s.items)
+	listeners, watchers := s.listeners, s.watchers
+	s.mu.Unlock()
+
+	for _, w := range watchers {
+		w.call(s, changes)
+	}
+	for _, l := range listeners {
+		l.call(s, items)
+	}
+}
+
+// Named gives s a debug name and registers it for DumpGraph.
+func (s *ObservableSlice[T]) Named(name string) *ObservableSlice[T] {
+	register(s, name)
+	return s
+}
+
+func (s *ObservableSlice[T]) inspect() nodeInfo {
+	s.mu.Lock()
+	subscribers := len(s.listeners) + len(s.watchers)
+	s.mu.Unlock()
+	return nodeInfo{
+		kind:        "slice",
+		subscribers: subscribers,
+		updated:     s.updated.time(),
+		observers:   s.observers.snapshot(),
+	}
+}
+
+// MapSlice returns a slice that mirrors src through f. Only the elements
+// touched by an edit are re-mapped.
+func MapSlice[T, U any](src *ObservableSlice[T], f func(T) U) (*ObservableSlice[U], func()) {
+	src.mu.Lock()
+	mapped := make([]U, len(src.items))
+	for i, v := range src.items {
+		mapped[i] = f(v)
+	}
+	src.mu.Unlock()
+
+	dst := &ObservableSlice[U]{items: mapped, id: nextNodeID()}
+	stop := src.Observe(func(changes []SliceChange[T]) {
+		if slices.ContainsFunc(changes, func(c SliceChange[T]) bool { return c.Op == SliceReset }) {
+			items := src.Get()
+			out := make([]U, len(items))
+			for i, v := range items {
+				out[i] = f(v)
+			}
+			dst.Set(out)
+			return
+		}
+		Batch(func() {
+			for _, c := range changes {
+				switch c.Op {
+				case SliceInsert:
+					dst.Insert(c.Index, f(c.Value))
+				case SliceRemove:
+					dst.RemoveAt(c.Index)
+				case SliceMove:
+					dst.Move(c.From, c.Index)
+				case SliceUpdate:
+					dst.Update(c.Index, f(c.Value))
+				}
+			}
+		})
+	})
+	return dst, stop
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSliceEdits(t *testing.T) {
+	s := NewObservableSlice("a", "b")
+	var ops []SliceOp
+	stop := s.Observe(func(changes []SliceChange[string]) {
+		for _, c := range changes {
+			ops = append(ops, c.Op)
+		}
+	})
+	defer stop()
+
+	s.Append("c")
+	s.Move(0, 2)
+	s.Update(0, "B")
+	s.RemoveAt(1)
+
+	if got, want := s.Get(), []string{"B", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+	if want := []SliceOp{SliceInsert, SliceMove, SliceUpdate, SliceRemove}; !slices.Equal(ops, want) {
+		t.Fatalf("ops = %v, want %v", ops, want)
+	}
+}
+
+func TestSliceOutOfRangeLeavesSliceUsable(t *testing.T) {
+	s := NewObservableSlice(1, 2)
+	for name, edit := range map[string]func(){
+		"RemoveAt": func() { s.RemoveAt(5) },
+		"Update":   func() { s.Update(-1, 0) },
+		"Insert":   func() { s.Insert(9, 0) },
+		"Move":     func() { s.Move(0, 7) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s out of range did not panic", name)
+				}
+			}()
+			edit()
+		}()
+	}
+
+	s.Append(3)
+	if got, want := s.Get(), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+//go:build js && wasm
+
+package core
+
+import (
+	"fmt"
+	"syscall/js"
+)
+
+// webStorage adapts a Web Storage object (localStorage or sessionStorage).
+type webStorage struct {
+	v js.Value
+}
+
+func init() {
+	if s := LocalStorage(); s != nil {
+		DefaultStorage = s
+	}
+}
+
+// LocalStorage returns the browser's localStorage, or nil if it is not
+// available (for example when storage is disabled).
+func LocalStorage() Storage { return openWebStorage("localStorage") }
+
+// SessionStorage returns the browser's sessionStorage, or nil if it is not
+// available.
+func SessionStorage() Storage { return openWebStorage("sessionStorage") }
+
+func openWebStorage(name string) (s Storage) {
+	defer func() {
+		if recover() != nil {
+			s = nil
+		}
+	}()
+	v := js.Global().Get(name)
+	if v.IsUndefined() || v.IsNull() {
+		return nil
+	}
+	return webStorage{v}
+}
+
+func (w webStorage) GetItem(key string) (string, bool) {
+	v := w.v.Call("getItem", key)
+	if v.IsNull() {
+		return "", false
+	}
+	return v.String(), true
+}
+
+func (w webStorage) SetItem(key, value string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if jsErr, ok := r.(js.Error); ok && isQuotaError(jsErr.Value) {
+				err = ErrQuotaExceeded
+				return
+			}
+			err = fmt.Errorf("setItem: %v", r)
+		}
+	}()
+	w.v.Call("setItem", key, value)
+	return nil
+}
+
+func (w webStorage) RemoveItem(key string) {
+	w.v.Call("removeItem", key)
+}
+
+func isQuotaError(v js.Value) bool {
+	name := v.Get("name")
+	if name.Type() != js.TypeString {
+		return false
+	}
+	switch name.String() {
+	case "QuotaExceededError", "NS_ERROR_DOM_QUOTA_REACHED":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package core
+
+// === Store ===
+
+// Reducer computes the next state from the current one and an action. It
+// must be pure: when dispatches race, it may be called again with the newer
+// state.
+type Reducer[S, A any] func(state S, action A) S
+
+// Dispatcher sends an action down a dispatch chain.
+type Dispatcher[A any] func(action A)
+
+// Middleware wraps the dispatch chain. It may inspect, transform, delay or
+// drop an action before passing it to next, and may dispatch new actions
+// through store.Dispatch.
+type Middleware[S, A any] func(store *Store[S, A], next Dispatcher[A]) Dispatcher[A]
+
+// Store is a central state container. State changes only by dispatching
+// actions through the reducer, and propagate like any Observable.
+type Store[S, A any] struct {
+	state    *Observable[S]
+	dispatch Dispatcher[A]
+}
+
+// NewStore creates a store holding initial. Middleware runs in the order
+// given, the first one seeing each action first.
+func NewStore[S, A any](initial S, reducer Reducer[S, A], middleware ...Middleware[S, A]) *Store[S, A] {
+	s := &Store[S, A]{state: NewObservable(initial)}
+	dispatch := Dispatcher[A](func(action A) {
+		s.state.update(func(state S) S {
+			return reducer(state, action)
+		})
+	})
+	for i := len(middleware) - 1; i >= 0; i-- {
+		dispatch = middleware[i](s, dispatch)
+	}
+	s.dispatch = dispatch
+	return s
+}
+
+// Dispatch sends action through the middleware chain to the reducer.
+func (s *Store[S, A]) Dispatch(action A) {
+	s.dispatch(action)
+}
+
+func (s *Store[S, A]) Get() S { return s.state.Get() }
+
+func (s *Store[S, A]) Subscribe(fn func(S)) func() { return s.state.Subscribe(fn) }
+
+// Select returns a memoized view of the store: selector reruns on every
+// state change, but the view only notifies when its result changes.
+func Select[S, A, R any](s *Store[S, A], selector func(S) R) *Derived[R] {
+	return Derive(func() R {
+		return selector(s.Get())
+	})
+}
+
+// SelectWithEqual is like Select but compares results with equal, for
+// selectors returning types that are not comparable with ==.
+func SelectWithEqual[S, A, R any](s *Store[S, A], selector func(S) R, equal func(a, b R) bool) *Derived[R] {
+	return DeriveWithEqual(func() R {
+		return selector(s.Get())
+	}, equal)
+}
+
+// === Middleware ===
+
+// Thunk is an action that is run instead of being reduced. It can dispatch
+// other actions, possibly later from another goroutine. To dispatch thunks,
+// the action type A must be an interface type that Thunk satisfies, such
+// as any.
+type Thunk[S, A any] func(store *Store[S, A])
+
+// ThunkMiddleware runs Thunk actions and passes every other action on.
+func ThunkMiddleware[S, A any]() Middleware[S, A] {
+	return func(store *Store[S, A], next Dispatcher[A]) Dispatcher[A] {
+		return func(action A) {
+			if thunk, ok := any(action).(Thunk[S, A]); ok {
+				thunk(store)
+				return
+			}
+			next(action)
+		}
+	}
+}
+
+// AfterDispatch calls fn with each action and the state it produced. Use it
+// for persistence or analytics.
+func AfterDispatch[S, A any](fn func(action A, state S)) Middleware[S, A] {
+	return func(store *Store[S, A], next Dispatcher[A]) Dispatcher[A] {
+		return func(action A) {
+			next(action)
+			fn(action, Untrack(store.Get))
+		}
+	}
+}
+
+// LoggingMiddleware reports every action and the state it produced
+// through logf, e.g. log.Printf.
+func LoggingMiddleware[S, A any](logf func(format string, args ...any)) Middleware[S, A] {
+	return AfterDispatch(func(action A, state S) {
+		logf("action %+v -> state %+v", action, state)
+	})
+}
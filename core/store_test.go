@@ -0,0 +1,104 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+type counter struct {
+	count int
+	label string
+}
+
+func reduceCounter(s counter, action any) counter {
+	switch a := action.(type) {
+	case int:
+		s.count += a
+	case string:
+		s.label = a
+	}
+	return s
+}
+
+func TestStoreDispatch(t *testing.T) {
+	store := NewStore[counter, any](counter{}, reduceCounter)
+	var seen []int
+	stop := store.Subscribe(func(s counter) { seen = append(seen, s.count) })
+	defer stop()
+
+	store.Dispatch(2)
+	store.Dispatch(3)
+	store.Dispatch("ignored by count")
+
+	if got := store.Get().count; got != 5 {
+		t.Fatalf("count = %d, want 5", got)
+	}
+	if want := []int{0, 2, 5, 5}; !slices.Equal(seen, want) {
+		t.Fatalf("seen = %v, want %v", seen, want)
+	}
+}
+
+func TestStoreMiddlewareOrder(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware[counter, any] {
+		return func(_ *Store[counter, any], next Dispatcher[any]) Dispatcher[any] {
+			return func(action any) {
+				order = append(order, name+" before")
+				next(action)
+				order = append(order, name+" after")
+			}
+		}
+	}
+	store := NewStore(counter{}, reduceCounter, tag("first"), tag("second"))
+
+	store.Dispatch(1)
+	want := []string{"first before", "second before", "second after", "first after"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+}
+
+func TestThunkMiddleware(t *testing.T) {
+	var after []string
+	store := NewStore(counter{}, reduceCounter,
+		ThunkMiddleware[counter, any](),
+		AfterDispatch(func(action any, s counter) {
+			after = append(after, fmt.Sprintf("%v:%d", action, s.count))
+		}))
+
+	store.Dispatch(Thunk[counter, any](func(s *Store[counter, any]) {
+		s.Dispatch(1)
+		s.Dispatch(s.Get().count + 10)
+	}))
+
+	if got := store.Get().count; got != 12 {
+		t.Fatalf("count = %d, want 12", got)
+	}
+	if want := []string{"1:1", "11:12"}; !slices.Equal(after, want) {
+		t.Fatalf("AfterDispatch saw %v, want %v", after, want)
+	}
+}
+
+func TestSelectNotifiesOnlyOnChange(t *testing.T) {
+	store := NewStore[counter, any](counter{}, reduceCounter)
+	runs := 0
+	label := Select(store, func(s counter) string {
+		runs++
+		return s.label
+	})
+	var seen []string
+	stop := label.Subscribe(func(v string) { seen = append(seen, v) })
+	defer stop()
+
+	store.Dispatch(1)
+	store.Dispatch("hello")
+	store.Dispatch(2)
+
+	if want := []string{"", "hello"}; !slices.Equal(seen, want) {
+		t.Fatalf("seen = %v, want %v", seen, want)
+	}
+	if runs != 4 {
+		t.Fatalf("selector ran %d times, want 4", runs)
+	}
+}
@@ -0,0 +1,240 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// === Time-based operators ===
+
+// Clock is the time source used by Debounce, Throttle and Delay. Tests can
+// pass a ManualClock through WithClock instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f after d and returns a func that cancels the call,
+	// reporting whether it was still pending.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// SystemClock is the real wall clock.
+var SystemClock Clock = systemClock{}
+
+// TimeOption configures a time-based operator.
+type TimeOption func(*timeConfig)
+
+type timeConfig struct {
+	clock Clock
+}
+
+// WithClock makes an operator use c instead of SystemClock.
+func WithClock(c Clock) TimeOption {
+	return func(cfg *timeConfig) { cfg.clock = c }
+}
+
+func newTimeConfig(opts []TimeOption) timeConfig {
+	cfg := timeConfig{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Debounce follows src, but only takes a value once src has been quiet for
+// d. Every change restarts the wait.
+func Debounce[T any](src ReadonlyObservable[T], d time.Duration, opts ...TimeOption) *Readonly[T] {
+	cfg := newTimeConfig(opts)
+	out := newReadonly(Untrack(src.Get))
+
+	var (
+		mu      sync.Mutex
+		pending func() bool
+	)
+	out.onDispose(changes(src, func(v T) {
+		mu.Lock()
+		defer mu.Unlock()
+		if pending != nil {
+			pending()
+		}
+		pending = cfg.clock.AfterFunc(d, func() { out.set(v) })
+	}))
+	out.onDispose(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if pending != nil {
+			pending()
+		}
+	})
+	return out
+}
+
+// Throttle follows src, taking at most one value per d. A change arriving
+// within d of the last one taken is held back and the latest such value is
+// taken when the window ends.
+func Throttle[T any](src ReadonlyObservable[T], d time.Duration, opts ...TimeOption) *Readonly[T] {
+	cfg := newTimeConfig(opts)
+	out := newReadonly(Untrack(src.Get))
+
+	var (
+		mu      sync.Mutex
+		last    time.Time
+		latest  T
+		pending func() bool
+	)
+	emit := func() {
+		mu.Lock()
+		v := latest
+		pending = nil
+		last = cfg.clock.Now()
+		mu.Unlock()
+		out.set(v)
+	}
+	out.onDispose(changes(src, func(v T) {
+		mu.Lock()
+		latest = v
+		if pending != nil {
+			mu.Unlock()
+			return
+		}
+		wait := d - cfg.clock.Now().Sub(last)
+		if wait <= 0 {
+			mu.Unlock()
+			emit()
+			return
+		}
+		pending = cfg.clock.AfterFunc(wait, emit)
+		mu.Unlock()
+	}))
+	out.onDispose(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if pending != nil {
+			pending()
+		}
+	})
+	return out
+}
+
+// Delay follows src, taking every value d after it was set, in order.
+func Delay[T any](src ReadonlyObservable[T], d time.Duration, opts ...TimeOption) *Readonly[T] {
+	cfg := newTimeConfig(opts)
+	out := newReadonly(Untrack(src.Get))
+
+	var (
+		mu      sync.Mutex
+		queue   []T
+		pending = map[int]func() bool{}
+		next    int
+	)
+	out.onDispose(changes(src, func(v T) {
+		mu.Lock()
+		defer mu.Unlock()
+		queue = append(queue, v)
+		id := next
+		next++
+		pending[id] = cfg.clock.AfterFunc(d, func() {
+			mu.Lock()
+			delete(pending, id)
+			if len(queue) == 0 {
+				mu.Unlock()
+				return
+			}
+			v := queue[0]
+			queue = queue[1:]
+			mu.Unlock()
+			out.set(v)
+		})
+	}))
+	out.onDispose(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, stop := range pending {
+			stop()
+		}
+		pending = map[int]func() bool{}
+		queue = nil
+	})
+	return out
+}
+
+// === ManualClock ===
+
+// ManualClock is a Clock that only moves when told to. Timers fire
+// synchronously from Advance, in the order they fall due.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+	seq    int
+}
+
+type manualTimer struct {
+	at  time.Time
+	seq int
+	f   func()
+}
+
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	t := &manualTimer{at: c.now.Add(d), seq: c.seq, f: f}
+	c.timers = append(c.timers, t)
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i, other := range c.timers {
+			if other == t {
+				c.timers = append(c.timers[:i], c.timers[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Advance moves the clock forward by d, firing every timer that falls due
+// on the way, including timers scheduled by the ones that fire.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		sort.SliceStable(c.timers, func(i, j int) bool {
+			if !c.timers[i].at.Equal(c.timers[j].at) {
+				return c.timers[i].at.Before(c.timers[j].at)
+			}
+			return c.timers[i].seq < c.timers[j].seq
+		})
+		if len(c.timers) == 0 || c.timers[0].at.After(target) {
+			c.now = target
+			c.mu.Unlock()
+			return
+		}
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		c.now = t.at
+		c.mu.Unlock()
+		t.f()
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestManualClockFiresInOrder(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	var fired []string
+	clock.AfterFunc(2*time.Second, func() { fired = append(fired, "b") })
+	clock.AfterFunc(time.Second, func() {
+		fired = append(fired, "a")
+		clock.AfterFunc(500*time.Millisecond, func() { fired = append(fired, "a2") })
+	})
+	stop := clock.AfterFunc(time.Second, func() { fired = append(fired, "stopped") })
+	if !stop() {
+		t.Fatal("stop reported the timer was not pending")
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	if want := []string{"a", "a2"}; !slices.Equal(fired, want) {
+		t.Fatalf("fired = %v, want %v", fired, want)
+	}
+	if got := clock.Now(); !got.Equal(time.Unix(1, 5e8)) {
+		t.Fatalf("Now = %v, want 1.5s", got)
+	}
+	clock.Advance(time.Second)
+	if want := []string{"a", "a2", "b"}; !slices.Equal(fired, want) {
+		t.Fatalf("fired = %v, want %v", fired, want)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	src := NewObservable("")
+	out := Debounce[string](src, 100*time.Millisecond, WithClock(clock))
+	defer out.Dispose()
+
+	src.Set("a")
+	clock.Advance(50 * time.Millisecond)
+	src.Set("ab")
+	clock.Advance(50 * time.Millisecond)
+	if got := out.Get(); got != "" {
+		t.Fatalf("before quiet period = %q, want empty", got)
+	}
+	clock.Advance(50 * time.Millisecond)
+	if got := out.Get(); got != "ab" {
+		t.Fatalf("after quiet period = %q, want ab", got)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	src := NewObservable(0)
+	out := Throttle[int](src, 100*time.Millisecond, WithClock(clock))
+	defer out.Dispose()
+	seen := record(t, out)
+
+	src.Set(1)
+	src.Set(2)
+	src.Set(3)
+	clock.Advance(100 * time.Millisecond)
+	if want := []int{0, 1, 3}; !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	src := NewObservable(0)
+	out := Delay[int](src, 100*time.Millisecond, WithClock(clock))
+	seen := record(t, out)
+
+	src.Set(1)
+	clock.Advance(50 * time.Millisecond)
+	src.Set(2)
+	clock.Advance(50 * time.Millisecond)
+	if want := []int{0, 1}; !slices.Equal(*seen, want) {
+		t.Fatalf("seen = %v, want %v", *seen, want)
+	}
+
+	out.Dispose()
+	clock.Advance(time.Second)
+	if got := out.Get(); got != 1 {
+		t.Fatalf("after Dispose = %d, want 1", got)
+	}
+}
+
+func TestDebounceInsideEffect(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	src := NewObservable("")
+	trigger := NewObservable(0)
+	runs := 0
+	dispose := Effect(func() func() {
+		trigger.Get()
+		runs++
+		Debounce[string](src, 100*time.Millisecond, WithClock(clock))
+		return nil
+	})
+	defer dispose()
+
+	src.Set("a")
+	trigger.Set(1)
+	if runs != 2 {
+		t.Fatalf("runs = %d, want 2: the Effect tracked the debounced source", runs)
+	}
+	if n := len(src.listeners); n != 1 {
+		t.Fatalf("src has %d listeners after re-running, want 1", n)
+	}
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"strings"
+	"sync"
+)
+
+// === Event bus ===
+
+// Event is a message published on a Bus.
+type Event struct {
+	Topic string
+	Data  any
+}
+
+// ListenOption configures a bus subscription.
+type ListenOption func(*busSub)
+
+// Deferred delivers events to the listener once the current propagation
+// completes, in publish order, instead of from within Publish: after the
+// synchronous listeners have run and the changes they made have reached
+// every dependent. Inside a Batch or a subscriber, that is after Publish
+// returns, when the surrounding flush ends; otherwise it is still before
+// Publish returns.
+func Deferred() ListenOption {
+	return func(s *busSub) { s.deferred = true }
+}
+
+type busSub struct {
+	id       int
+	pattern  []string
+	fn       func(Event)
+	once     bool
+	deferred bool
+	scope    *scope
+	// accept, if set, rejects events the listener cannot handle. A rejected
+	// event does not use up a Once subscription.
+	accept func(Event) bool
+}
+
+// call delivers e, reporting a panic in the listener instead of letting it
+// reach the publisher.
+func (s *busSub) call(e Event) {
+	defer recoverTo(s.scope, "listener for "+e.Topic)
+	s.fn(e)
+}
+
+// Bus is a publish/subscribe hub for app-wide events. Topics are names made
+// of segments separated by ':', such as "user:logged-in". Subscription
+// patterns may use "*" to match any single segment and a trailing "**" to
+// match any number of remaining segments, so "user:*" matches
+// "user:logged-in" and "**" matches everything.
+type Bus struct {
+	mu     sync.Mutex
+	subs   []*busSub
+	nextID int
+}
+
+// DefaultBus is used by Publish, Subscribe and NewTopic.
+var DefaultBus = NewBus()
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Publish delivers data to every listener whose pattern matches topic.
+// Like a Batch, changes made by the listeners propagate once they have all
+// run.
+func (b *Bus) Publish(topic string, data any) {
+	e := Event{Topic: topic, Data: data}
+	segments := strings.Split(topic, ":")
+
+	b.mu.Lock()
+	var matched []*busSub
+	for _, s := range b.subs {
+		if matchTopic(s.pattern, segments) && (s.accept == nil || s.accept(e)) {
+			matched = append(matched, s)
+		}
+	}
+	b.mu.Unlock()
+
+	startBatch()
+	defer endBatch()
+	for _, s := range matched {
+		if s.once && !b.remove(s.id) {
+			continue
+		}
+		if s.deferred {
+			onFlushed(func() { s.call(e) })
+		} else {
+			s.call(e)
+		}
+	}
+}
+
+// Subscribe calls fn for every event whose topic matches pattern. The
+// returned func removes the subscription.
+func (b *Bus) Subscribe(pattern string, fn func(Event), opts ...ListenOption) func() {
+	return b.add(&busSub{pattern: strings.Split(pattern, ":"), fn: fn}, opts)
+}
+
+// Once is like Subscribe but removes the subscription after the first
+// matching event.
+func (b *Bus) Once(pattern string, fn func(Event), opts ...ListenOption) func() {
+	return b.add(&busSub{pattern: strings.Split(pattern, ":"), fn: fn, once: true}, opts)
+}
+
+func (b *Bus) add(s *busSub, opts []ListenOption) func() {
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.scope = currentScope()
+	b.mu.Lock()
+	b.nextID++
+	s.id = b.nextID
+	b.subs = append(b.subs, s)
+	b.mu.Unlock()
+	return func() { b.remove(s.id) }
+}
+
+// remove drops the subscription with id and reports whether it was there.
+func (b *Bus) remove(id int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.subs {
+		if s.id == id {
+			b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func matchTopic(pattern, topic []string) bool {
+	for i, p := range pattern {
+		if p == "**" && i == len(pattern)-1 {
+			return true
+		}
+		if i >= len(topic) || (p != "*" && p != topic[i]) {
+			return false
+		}
+	}
+	return len(pattern) == len(topic)
+}
+
+// Publish publishes data under topic on DefaultBus.
+func Publish(topic string, data any) {
+	DefaultBus.Publish(topic, data)
+}
+
+// Subscribe calls fn with the data of every event on DefaultBus whose topic
+// matches pattern.
+func Subscribe(pattern string, fn func(data any), opts ...ListenOption) func() {
+	return DefaultBus.Subscribe(pattern, func(e Event) { fn(e.Data) }, opts...)
+}
+
+// Topic is a typed handle on a bus topic, so publishers and listeners
+// agree on the payload type without sharing anything but the name.
+type Topic[T any] struct {
+	name string
+	bus  *Bus
+}
+
+// NewTopic returns the topic called name on DefaultBus.
+func NewTopic[T any](name string) *Topic[T] {
+	return NewTopicOn[T](DefaultBus, name)
+}
+
+// NewTopicOn returns the topic called name on b.
+func NewTopicOn[T any](b *Bus, name string) *Topic[T] {
+	return &Topic[T]{name: name, bus: b}
+}
+
+func (t *Topic[T]) Name() string { return t.name }
+
+func (t *Topic[T]) Publish(v T) {
+	t.bus.Publish(t.name, v)
+}
+
+// Subscribe calls fn with every value published on t. Events published
+// under the same name with a different payload type are ignored.
+func (t *Topic[T]) Subscribe(fn func(T), opts ...ListenOption) func() {
+	return t.bus.Subscribe(t.name, typedListener(fn), append(opts[:len(opts):len(opts)], acceptType[T])...)
+}
+
+// Once is like Subscribe but only delivers the first value of type T.
+func (t *Topic[T]) Once(fn func(T), opts ...ListenOption) func() {
+	return t.bus.Once(t.name, typedListener(fn), append(opts[:len(opts):len(opts)], acceptType[T])...)
+}
+
+// acceptType makes a subscription ignore events whose data is not a T.
+func acceptType[T any](s *busSub) {
+	s.accept = func(e Event) bool {
+		_, ok := e.Data.(T)
+		return ok
+	}
+}
+
+func typedListener[T any](fn func(T)) func(Event) {
+	return func(e Event) { fn(e.Data.(T)) }
+}
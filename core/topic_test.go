@@ -0,0 +1,104 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+// topics subscribes to pattern on b and returns the topics delivered.
+func topics(t *testing.T, b *Bus, pattern string, opts ...ListenOption) *[]string {
+	t.Helper()
+	var got []string
+	t.Cleanup(b.Subscribe(pattern, func(e Event) { got = append(got, e.Topic) }, opts...))
+	return &got
+}
+
+func TestBusPatterns(t *testing.T) {
+	b := NewBus()
+	exact := topics(t, b, "user:login")
+	single := topics(t, b, "user:*")
+	rest := topics(t, b, "user:**")
+	all := topics(t, b, "**")
+
+	for _, topic := range []string{"user:login", "user:logout", "user:profile:saved", "cart:add"} {
+		b.Publish(topic, nil)
+	}
+
+	for _, c := range []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"exact", *exact, []string{"user:login"}},
+		{"*", *single, []string{"user:login", "user:logout"}},
+		{"user:**", *rest, []string{"user:login", "user:logout", "user:profile:saved"}},
+		{"**", *all, []string{"user:login", "user:logout", "user:profile:saved", "cart:add"}},
+	} {
+		if !slices.Equal(c.got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestBusUnsubscribeAndOnce(t *testing.T) {
+	b := NewBus()
+	var subs, once int
+	stop := b.Subscribe("tick", func(Event) { subs++ })
+	b.Once("tick", func(Event) { once++ })
+
+	b.Publish("tick", nil)
+	stop()
+	b.Publish("tick", nil)
+
+	if subs != 1 || once != 1 {
+		t.Fatalf("subscriber ran %d times, once-listener %d, want 1 and 1", subs, once)
+	}
+}
+
+func TestTopicIgnoresOtherTypes(t *testing.T) {
+	b := NewBus()
+	count := NewTopicOn[int](b, "count")
+	var got []int
+	count.Once(func(v int) { got = append(got, v) })
+	stop := count.Subscribe(func(v int) { got = append(got, v*10) })
+	defer stop()
+
+	b.Publish("count", "not an int")
+	count.Publish(5)
+	count.Publish(6)
+
+	if want := []int{5, 50, 60}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestBusDeferredRunsAfterPropagation(t *testing.T) {
+	b := NewBus()
+	n := NewObservable(0)
+	double := Derive(func() int { return n.Get() * 2 })
+
+	var order []string
+	b.Subscribe("set", func(e Event) {
+		n.Set(e.Data.(int))
+		order = append(order, "sync")
+	})
+	b.Subscribe("set", func(Event) {
+		order = append(order, "deferred saw "+string(rune('0'+double.Get())))
+	}, Deferred())
+
+	Batch(func() {
+		b.Publish("set", 3)
+		if len(order) != 1 {
+			t.Errorf("deferred listener ran inside the batch: %v", order)
+		}
+	})
+	if want := []string{"sync", "deferred saw 6"}; !slices.Equal(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+
+	order = nil
+	b.Publish("set", 4)
+	if want := []string{"sync", "deferred saw 8"}; !slices.Equal(order, want) {
+		t.Fatalf("outside a batch: order = %v, want %v", order, want)
+	}
+}
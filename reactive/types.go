@@ -2,5 +2,5 @@ package reactive
 
 type ReadonlyObservable[T any] interface {
 	Get() T
-	Subscribe(func(T))
+	Subscribe(func(T)) func()
 }
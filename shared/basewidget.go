@@ -2,6 +2,7 @@ package shared
 
 import (
 	"fmt"
+	"gocore/core"
 	"strings"
 
 	dom "honnef.co/go/js/dom/v2"
@@ -9,13 +10,15 @@ import (
 
 type ReadonlyObservable[T any] interface {
 	Get() T
-	Subscribe(func(T))
+	Subscribe(func(T)) func()
 }
 
 type BaseWidget struct {
 	Inner     dom.HTMLElement
 	El        dom.HTMLElement
 	IsWrapped bool
+
+	disposers []func()
 }
 
 func (b *BaseWidget) Element() dom.HTMLElement {
@@ -101,10 +104,26 @@ func (b *BaseWidget) Remove() {
 	}
 }
 
+// Own registers dispose to be called when the widget is disposed.
+func (b *BaseWidget) Own(dispose func()) {
+	b.disposers = append(b.disposers, dispose)
+}
+
+// Dispose removes the widget from the DOM and releases its bindings. The ui
+// containers register the children added to them with Own, so disposing
+// one disposes its whole subtree. Disposing twice is harmless.
+func (b *BaseWidget) Dispose() {
+	b.Remove()
+	for _, dispose := range b.disposers {
+		dispose()
+	}
+	b.disposers = nil
+}
+
 func (b *BaseWidget) BindText(obs ReadonlyObservable[string]) {
-	obs.Subscribe(func(val string) {
+	b.Own(core.Bind(obs, func(val string) {
 		b.SetText(val)
-	})
+	}))
 }
 
 func (b *BaseWidget) wrapWithStyle(styles map[string]string) *BaseWidget {
@@ -186,9 +205,9 @@ type Widget interface {
 //}
 
 func (b *BaseWidget) BindStyle(obs ReadonlyObservable[map[string]string]) {
-	obs.Subscribe(func(styles map[string]string) {
+	b.Own(core.Bind(obs, func(styles map[string]string) {
 		for prop, val := range styles {
 			b.El.Style().SetProperty(prop, val, "")
 		}
-	})
+	}))
 }
@@ -72,13 +72,17 @@ func (t *TextField) Text() string {
 	return t.input.Value()
 }
 
-func (t *TextField) BindTo(obs *core.Observable[string]) {
-	obs.Subscribe(func(val string) {
+func (t *TextField) BindTo(obs core.Writable[string]) {
+	t.Own(core.Bind(obs, func(val string) {
 		t.input.SetValue(val)
-	})
-	t.input.AddEventListener("input", false, func(dom.Event) {
+	}))
+	onInput := t.input.AddEventListener("input", false, func(dom.Event) {
 		obs.Set(t.Text())
 	})
+	t.Own(func() {
+		t.input.RemoveEventListener("input", false, onInput)
+		onInput.Release()
+	})
 }
 
 func (d *TextField) Padding(px int) *TextField { d.BaseWidget = d.BaseWidget.Padding(px); return d }
@@ -112,6 +116,7 @@ func NewDiv() *Div {
 func (d *Div) Add(children ...Widget) {
 	for _, c := range children {
 		d.El.AppendChild(c.Element())
+		adoptChild(d.BaseWidget, c)
 	}
 }
 
@@ -146,6 +151,7 @@ func NewVBox(children ...Widget) *VBox {
 func (v *VBox) Add(children ...Widget) {
 	for _, c := range children {
 		v.El.AppendChild(c.Element())
+		adoptChild(v.BaseWidget, c)
 	}
 }
 
@@ -179,6 +185,7 @@ func NewHBox(children ...Widget) *HBox {
 func (h *HBox) Add(children ...Widget) {
 	for _, c := range children {
 		h.El.AppendChild(c.Element())
+		adoptChild(h.BaseWidget, c)
 	}
 }
 
@@ -211,13 +218,17 @@ func (t *TextArea) SetText(val string) {
 	t.area.SetValue(val)
 }
 
-func (t *TextArea) BindTo(obs *core.Observable[string]) {
-	obs.Subscribe(func(val string) {
+func (t *TextArea) BindTo(obs core.Writable[string]) {
+	t.Own(core.Bind(obs, func(val string) {
 		t.area.SetValue(val)
-	})
-	t.area.AddEventListener("input", false, func(dom.Event) {
+	}))
+	onInput := t.area.AddEventListener("input", false, func(dom.Event) {
 		obs.Set(t.Text())
 	})
+	t.Own(func() {
+		t.area.RemoveEventListener("input", false, onInput)
+		onInput.Release()
+	})
 }
 
 func (t *TextArea) Padding(px int) *TextArea { t.BaseWidget = t.BaseWidget.Padding(px); return t }
@@ -267,8 +278,8 @@ func (b *TextArea) Width(percent string) *TextArea {
 }
 
 // Updated BindText
-func BindText(el dom.HTMLElement, obs core.ReadonlyObservable[string]) {
-	obs.Subscribe(func(val string) {
+func BindText(el dom.HTMLElement, obs core.ReadonlyObservable[string]) func() {
+	return core.Bind(obs, func(val string) {
 		el.SetTextContent(val)
 	})
 }
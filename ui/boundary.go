@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"gocore/core"
+	"gocore/shared"
+
+	dom "honnef.co/go/js/dom/v2"
+)
+
+// Boundary shows a child widget until something inside it fails, then
+// shows a fallback instead.
+type Boundary struct {
+	*shared.BaseWidget
+	current Widget
+	failed  bool
+	release func()
+}
+
+// ErrorBoundary builds the widget returned by child and replaces it with
+// fallback(err) when child panics, or when a binding, Derive or Effect
+// created while building it panics later on. Only the first error is
+// shown; the failed subtree is disposed.
+func ErrorBoundary(child func() Widget, fallback func(err error) Widget) *Boundary {
+	doc := dom.GetWindow().Document()
+	el := doc.CreateElement("div").(dom.HTMLElement)
+	el.Style().SetProperty("display", "contents", "")
+
+	b := &Boundary{BaseWidget: &shared.BaseWidget{Inner: el, El: el}}
+	build := captureProviders()
+	b.release = core.Catch(func(err error) {
+		if b.failed {
+			return
+		}
+		b.failed = true
+		var w Widget
+		build(func() { w = fallback(err) })
+		b.show(w)
+		if b.release != nil {
+			b.release()
+		}
+	}, func() {
+		if w := child(); !b.failed {
+			b.show(w)
+		} else {
+			disposeRow(w)
+		}
+	})
+	if b.failed {
+		b.release()
+	}
+	b.Own(func() {
+		b.release()
+		disposeRow(b.current)
+	})
+	return b
+}
+
+// show replaces the current content with w.
+func (b *Boundary) show(w Widget) {
+	if b.current != nil {
+		b.Inner.RemoveChild(b.current.Element())
+		disposeRow(b.current)
+	}
+	b.current = w
+	b.Inner.AppendChild(w.Element())
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"gocore/core"
+	"gocore/shared"
+
+	dom "honnef.co/go/js/dom/v2"
+)
+
+// List renders one row per item of an ObservableSlice and patches the DOM
+// for each edit instead of rebuilding every row.
+type List struct {
+	*shared.BaseWidget
+	rows []Widget
+}
+
+func NewList[T any](items *core.ObservableSlice[T], render func(T) Widget) *List {
+	doc := dom.GetWindow().Document()
+	container := doc.CreateElement("div").(dom.HTMLElement)
+	container.Style().SetProperty("display", "flex", "")
+	container.Style().SetProperty("flex-direction", "column", "")
+	container.Style().SetProperty("gap", "0.5rem", "")
+
+	l := &List{BaseWidget: &shared.BaseWidget{Inner: container, El: container}}
+
+	// Rows are rendered later too, so they see the values provided here.
+	build := captureProviders()
+	renderRow := func(item T) (row Widget) {
+		build(func() { row = render(item) })
+		return row
+	}
+
+	reset := func() {
+		for _, row := range l.rows {
+			disposeRow(row)
+		}
+		l.rows = nil
+		for _, item := range items.Get() {
+			l.insert(len(l.rows), renderRow(item))
+		}
+	}
+	reset()
+
+	l.Own(items.Observe(func(changes []core.SliceChange[T]) {
+		for _, c := range changes {
+			switch c.Op {
+			case core.SliceInsert:
+				l.insert(c.Index, renderRow(c.Value))
+			case core.SliceRemove:
+				disposeRow(l.remove(c.Index))
+			case core.SliceMove:
+				l.insert(c.Index, l.remove(c.From))
+			case core.SliceUpdate:
+				disposeRow(l.remove(c.Index))
+				l.insert(c.Index, renderRow(c.Value))
+			case core.SliceReset:
+				reset()
+				return
+			}
+		}
+	}))
+	l.Own(func() {
+		for _, row := range l.rows {
+			disposeRow(row)
+		}
+		l.rows = nil
+	})
+	return l
+}
+
+func (l *List) insert(i int, row Widget) {
+	if i < len(l.rows) {
+		l.Inner.InsertBefore(row.Element(), l.rows[i].Element())
+	} else {
+		l.Inner.AppendChild(row.Element())
+	}
+	l.rows = append(l.rows[:i], append([]Widget{row}, l.rows[i:]...)...)
+}
+
+func (l *List) remove(i int) Widget {
+	row := l.rows[i]
+	l.Inner.RemoveChild(row.Element())
+	l.rows = append(l.rows[:i], l.rows[i+1:]...)
+	return row
+}
+
+// disposeRow releases a row's bindings if it supports it.
+func disposeRow(row Widget) {
+	if d, ok := row.(interface{ Dispose() }); ok {
+		d.Dispose()
+	}
+}
+
+// adoptChild makes disposing parent dispose child as well, so that the
+// bindings and provided values of a whole subtree go with its root.
+func adoptChild(parent *shared.BaseWidget, child Widget) {
+	if d, ok := child.(interface{ Dispose() }); ok {
+		parent.Own(d.Dispose)
+	}
+}
+
+func (l *List) Padding(px int) *List      { l.BaseWidget = l.BaseWidget.Padding(px); return l }
+func (l *List) Background(c string) *List { l.BaseWidget = l.BaseWidget.Background(c); return l }
+func (l *List) Border(s string) *List     { l.BaseWidget = l.BaseWidget.Border(s); return l }
+func (l *List) Center() *List             { l.BaseWidget = l.BaseWidget.Center(); return l }
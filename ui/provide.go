@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+
+	"gocore/shared"
+
+	dom "honnef.co/go/js/dom/v2"
+)
+
+// Provider makes a value available to every widget built or mounted below
+// it.
+type Provider struct {
+	*shared.BaseWidget
+}
+
+const providerAttr = "data-provider"
+
+// provision is one value provided while widgets are being built.
+type provision struct {
+	key, value any
+}
+
+var (
+	providersMu  sync.Mutex
+	providers    = map[string]map[any]any{}
+	nextProvider int
+	// building holds the values provided around the widgets currently being
+	// built, outermost first.
+	building []provision
+)
+
+// Provide builds the widget returned by child so that Inject(w, key)
+// returns value for any widget w inside it, including from constructors
+// that run while child builds it, before anything is mounted. The wrapper
+// uses display: contents and does not affect layout. Observables are
+// provided by pointer, so injected ones stay reactive. The value is
+// released when the Provider is disposed, which also disposes the child,
+// or when a container it was added to is.
+func Provide(key, value any, child func() Widget) *Provider {
+	doc := dom.GetWindow().Document()
+	el := doc.CreateElement("div").(dom.HTMLElement)
+	el.Style().SetProperty("display", "contents", "")
+
+	providersMu.Lock()
+	nextProvider++
+	id := strconv.Itoa(nextProvider)
+	providers[id] = map[any]any{key: value}
+	outer := building
+	providersMu.Unlock()
+
+	var w Widget
+	buildWith(append(slices.Clip(outer), provision{key, value}), func() { w = child() })
+
+	el.SetAttribute(providerAttr, id)
+	el.AppendChild(w.Element())
+
+	p := &Provider{&shared.BaseWidget{Inner: el, El: el}}
+	adoptChild(p.BaseWidget, w)
+	p.Own(func() {
+		providersMu.Lock()
+		defer providersMu.Unlock()
+		delete(providers, id)
+	})
+	return p
+}
+
+// buildWith runs build with provided as the values visible to Inject from
+// widgets that are not mounted yet.
+func buildWith(provided []provision, build func()) {
+	providersMu.Lock()
+	prev := building
+	building = provided
+	providersMu.Unlock()
+
+	defer func() {
+		providersMu.Lock()
+		building = prev
+		providersMu.Unlock()
+	}()
+	build()
+}
+
+// captureProviders returns a func that runs build with the values provided
+// where captureProviders was called, so that widgets a container builds
+// later, such as List rows, can inject them too.
+func captureProviders() func(build func()) {
+	providersMu.Lock()
+	provided := building
+	providersMu.Unlock()
+	return func(build func()) { buildWith(provided, build) }
+}
+
+// Inject returns the value provided under key by the nearest Provider above
+// w. A mounted w is resolved through its ancestors in the DOM; one that is
+// still being built, e.g. from its constructor, through the Provide calls
+// building it. Inject reports false if there is no such value or if it is
+// not a T.
+func Inject[T any](w Widget, key any) (T, bool) {
+	var zero T
+	for el := dom.Element(w.Element()); el != nil; el = el.ParentElement() {
+		id := el.GetAttribute(providerAttr)
+		if id == "" {
+			continue
+		}
+		providersMu.Lock()
+		value, ok := providers[id][key]
+		providersMu.Unlock()
+		if ok {
+			v, ok := value.(T)
+			return v, ok
+		}
+	}
+
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	for i := len(building) - 1; i >= 0; i-- {
+		if building[i].key == key {
+			v, ok := building[i].value.(T)
+			return v, ok
+		}
+	}
+	return zero, false
+}